@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"../../pkg/dledger"
+	"../../pkg/hashgraph"
+)
+
+const (
+	statusPollPeriod = 500 * time.Millisecond // the amount of time.sleep milliseconds between each status query
+)
+
+// Submits a transfer to a member of the distributed ledger and follows its status until it reaches consensus.
+// The transfer is signed with the key of the sender, so the member it is submitted to does not need to be the sender.
+func main() {
+	if len(os.Args) < 5 || len(os.Args) > 7 {
+		fmt.Println("Usage: client MEMBER_ADDRESS KEY_FILE RECEIVER_ID AMOUNT [PEERS_FILE [CA_FILE]]")
+		os.Exit(1)
+	}
+	memberAddress := os.Args[1]
+	privateKey := dledger.ReadPrivateKey(os.Args[2])
+	amount, err := strconv.ParseUint(os.Args[4], 10, 64)
+	handleError(err)
+
+	// Connections to members that use TLS are encrypted, and the certificate of the member is verified with the peers
+	// file and the CAs. The client does not need a certificate, only members need one to gossip.
+	var tlsConfig *dledger.TLSConfig
+	var peers map[hashgraph.MemberID]dledger.Peer
+	if len(os.Args) > 5 {
+		caFilePath := ""
+		if len(os.Args) > 6 {
+			caFilePath = os.Args[6]
+		}
+		peers = dledger.ReadPeers(os.Args[5], "localhost")
+		tlsConfig, err = dledger.LoadTLSConfig("", "", caFilePath)
+		handleError(err)
+	}
+
+	client, err := dledger.DialTransactions(dledger.TCPTransport{}, memberAddress, tlsConfig, peers)
+	handleError(err)
+	defer func() {
+		_ = client.Close()
+	}()
+
+	// The transfer takes the nonce after the last committed transfer of the sender
+	senderID := hashgraph.NewMemberID(privateKey.Public().(ed25519.PublicKey))
+	lastNonce, err := client.Nonce(senderID)
+	handleError(err)
+	transfer := dledger.Transfer{
+		SenderID:   senderID,
+		ReceiverID: hashgraph.MemberID(os.Args[3]),
+		Amount:     amount,
+		Nonce:      lastNonce + 1,
+	}
+	transfer.Sign(privateKey)
+
+	id, err := client.Submit(transfer)
+	handleError(err)
+	fmt.Printf("Submitted transaction %s\n", id)
+
+	lastState := ""
+	for {
+		status, err := client.Status(id)
+		handleError(err)
+		if status.State != lastState {
+			lastState = status.State
+			switch status.State {
+			case dledger.TxPending:
+				fmt.Println("Pending in the buffer of the member")
+			case dledger.TxInEvent:
+				fmt.Printf("Included in event %s\n", status.EventHash)
+			case dledger.TxConsensus:
+				fmt.Printf("Reached consensus in event %s at round %d with timestamp %s, applied: %t\n",
+					status.EventHash, status.RoundReceived, status.ConsensusTimestamp.Format(time.RFC3339Nano), status.Applied)
+				return
+			default:
+				fmt.Println("The member does not know the transaction")
+				return
+			}
+		}
+		time.Sleep(statusPollPeriod)
+	}
+}
+
+func handleError(e error) {
+	if e != nil {
+		panic(e)
+	}
+}
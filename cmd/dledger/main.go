@@ -2,39 +2,69 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
 	"../../pkg/dledger"
 )
 
-const (
-	defaultPort = "8080" // Use this default port if it isn't specified via command line arguments.
-)
-
 func main() {
-	port := defaultPort
-	if len(os.Args) > 1 {
-		port = os.Args[1]
+	config, err := dledger.ParseFlags(flag.CommandLine, os.Args[1:], dledger.DefaultConfig())
+	if err != nil {
+		fmt.Println("Bad settings: " + err.Error())
+		os.Exit(2)
+	}
+
+	distributedLedger := dledger.NewDLedger(config)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err := distributedLedger.WaitForPeers(ctx); err != nil {
+		fmt.Println("\nStopped while waiting for peers: " + err.Error())
+		_ = distributedLedger.Stop()
+		return
 	}
+	fmt.Printf("I am online at %s and peers with more than 2/3 of the stake are available.\n", distributedLedger.MyAddress)
+	distributedLedger.Start(ctx)
 
-	distributedLedger := dledger.NewDLedger(port, "peers.txt")
+	// Generate random transfers for evaluation
+	loadGenerator := dledger.NewLoadGenerator(distributedLedger)
+	loadGenerator.Rate = config.LoadRate
+	loadGenerator.Start()
 
-	distributedLedger.WaitForPeers()
-	fmt.Printf("I am online at %s and all peers are available.\n", distributedLedger.MyAddress)
-	distributedLedger.Start()
+	// Stop gracefully on an interrupt, so that the transfers submitted to me reach my peers. I also stop by myself if my
+	// store fails.
+	go func() {
+		select {
+		case <-ctx.Done():
+			fmt.Println("\nStopping...")
+		case <-distributedLedger.Done():
+		}
+		loadGenerator.Stop()
+		if err := distributedLedger.Stop(); err != nil {
+			fmt.Println("Stopped with an error: " + err.Error())
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}()
 
 	// Routine for user transaction inputs
 	var input int
-	var amount float64
+	var amount uint64
 	scanner := bufio.NewScanner(os.Stdin)
 	fmt.Println()
 	for {
-		// note: peerAddressMap contains me, but peerAddresses does not
-		fmt.Printf("\nDear %s, please choose a client for your new transaction.\n", distributedLedger.PeerAddressMap[distributedLedger.MyAddress])
-		for i, addr := range distributedLedger.PeerAddresses {
-			fmt.Printf("\t%d) %s\n", i+1, distributedLedger.PeerAddressMap[addr])
+		// note: PeerNames contains me, but PeerIDs does not
+		fmt.Printf("\nDear %s, please choose a client for your new transaction.\n", distributedLedger.PeerNames[distributedLedger.MyID])
+		fmt.Printf("\t0) Show balances and peers\n")
+		for i, id := range distributedLedger.PeerIDs {
+			fmt.Printf("\t%d) %s\n", i+1, distributedLedger.PeerNames[id])
 		}
 
 		fmt.Printf("Enter a number: > ")
@@ -44,29 +74,62 @@ func main() {
 			errForInput = false
 			scanner.Scan()
 			input, err = strconv.Atoi(scanner.Text())
-			if err != nil || input <= 0 || input > len(distributedLedger.PeerAddresses) {
+			if err != nil || input < 0 || input > len(distributedLedger.PeerIDs) {
 				errForInput = true
 				fmt.Printf("\nBad input, try again: > ")
 			}
 		}
-		chosenAddr := distributedLedger.PeerAddresses[input-1]
+		if input == 0 {
+			printBalances(distributedLedger)
+			printPeerHealth(distributedLedger)
+			continue
+		}
+		chosenID := distributedLedger.PeerIDs[input-1]
 
 		fmt.Printf("\nDear %s, please enter how much credits would you like transfer to %s:\n\t> ",
-			distributedLedger.PeerAddressMap[distributedLedger.MyAddress], distributedLedger.PeerAddressMap[chosenAddr])
+			distributedLedger.PeerNames[distributedLedger.MyID], distributedLedger.PeerNames[chosenID])
 		errForInput = true
 		for errForInput {
 			var err error
 			errForInput = false
 			scanner.Scan()
-			amount, err = strconv.ParseFloat(scanner.Text(), 64)
-			if err != nil || amount <= 0 {
+			amount, err = strconv.ParseUint(scanner.Text(), 10, 64)
+			if err != nil || amount == 0 {
 				errForInput = true
 				fmt.Printf("Bad input, try again: > ")
 			}
 		}
 
-		distributedLedger.PerformTransaction(chosenAddr, amount)
-		fmt.Printf("\nSuccessfully added transaction:\n\t'%s sends %f to %s'\n", distributedLedger.PeerAddressMap[distributedLedger.MyAddress], amount, distributedLedger.PeerAddressMap[chosenAddr])
+		id, err := distributedLedger.PerformTransaction(chosenID, amount)
+		if err != nil {
+			fmt.Printf("\nCould not add transaction: %s\n", err.Error())
+			continue
+		}
+		fmt.Printf("\nSuccessfully added transaction %s:\n\t'%s sends %d to %s'\n", id, distributedLedger.PeerNames[distributedLedger.MyID], amount, distributedLedger.PeerNames[chosenID])
+	}
+
+}
+
+// Prints the balances of all members as of the last round that reached consensus
+func printBalances(distributedLedger *dledger.DLedger) {
+	balances := distributedLedger.Ledger.Balances()
+	applied, rejected := distributedLedger.Ledger.TransferCounts()
+	fmt.Printf("\nBalances after round %d (%d transfers applied, %d rejected):\n", distributedLedger.Ledger.LastCommittedRound(), applied, rejected)
+	fmt.Printf("\t%s: %d (me)\n", distributedLedger.PeerNames[distributedLedger.MyID], balances[distributedLedger.MyID])
+	for _, id := range distributedLedger.PeerIDs {
+		fmt.Printf("\t%s: %d\n", distributedLedger.PeerNames[id], balances[id])
 	}
+}
 
+// Prints whether each peer is reachable for gossip, and why the unreachable peers are skipped
+func printPeerHealth(distributedLedger *dledger.DLedger) {
+	fmt.Printf("\nPeers:\n")
+	for _, health := range distributedLedger.PeerHealth() {
+		if health.Reachable {
+			fmt.Printf("\t%s: reachable at %s\n", health.Name, health.Address)
+		} else {
+			fmt.Printf("\t%s: unreachable after %d failed gossips, retrying in %s: %s\n", health.Name, health.ConsecutiveFailures,
+				time.Until(health.RetryAt).Round(time.Millisecond), health.LastError)
+		}
+	}
 }
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	defaultKeyFilePath = "member.key"           // Write the private key here if a path isn't specified via command line arguments.
+	demoPeersFileName  = "peersBackupLOCAL.txt" // Peers file of the demo members, which is written to the demo directory
+	demoKeysDirName    = "keys"                 // Keys of the demo members are written to this directory in the demo directory
+)
+
+// Demo members run on one device, each listening on its own port
+var demoMembers = []struct {
+	name    string
+	address string
+}{
+	{"Alice", "localhost:6060"},
+	{"Bob", "localhost:7070"},
+	{"Carol", "localhost:8080"},
+	{"Dave", "localhost:9090"},
+}
+
+// Generates an ed25519 key pair for a member. The private key seed is written to a file and the public key is printed,
+// so that it can be added to the peers file of every member. With -demo, generates the keys and the peers file of the
+// demo members on this device instead.
+func main() {
+	demoDir := flag.String("demo", "", "generate the keys and the peers file of the local demo members in this `directory`")
+	flag.Parse()
+	if *demoDir != "" {
+		generateDemo(*demoDir)
+		return
+	}
+
+	keyFilePath := defaultKeyFilePath
+	if flag.NArg() > 0 {
+		keyFilePath = flag.Arg(0)
+	}
+	publicKey := generateKey(keyFilePath)
+	fmt.Printf("Private key is written to %s\n", keyFilePath)
+	fmt.Printf("Public key: %s\n", hex.EncodeToString(publicKey))
+}
+
+// Writes a new key for every demo member and a peers file that lists them. Keys are generated on every run and never
+// shared, so nobody else can sign events for the demo members.
+func generateDemo(dir string) {
+	keysDir := filepath.Join(dir, demoKeysDirName)
+	handleError(os.MkdirAll(keysDir, 0700))
+
+	var peers strings.Builder
+	for _, member := range demoMembers {
+		publicKey := generateKey(filepath.Join(keysDir, member.name+".key"))
+		fmt.Fprintf(&peers, "%s %s %s\n", member.address, member.name, hex.EncodeToString(publicKey))
+	}
+	peersFilePath := filepath.Join(dir, demoPeersFileName)
+	handleError(ioutil.WriteFile(peersFilePath, []byte(peers.String()), 0644))
+
+	fmt.Printf("Keys of the demo members are written to %s\n", keysDir)
+	fmt.Printf("Peers of the demo members are written to %s\n", peersFilePath)
+}
+
+// Writes the seed of a new private key to the given file, returns the public key
+func generateKey(keyFilePath string) ed25519.PublicKey {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	handleError(err)
+	handleError(ioutil.WriteFile(keyFilePath, []byte(hex.EncodeToString(privateKey.Seed())+"\n"), 0600))
+	return publicKey
+}
+
+func handleError(e error) {
+	if e != nil {
+		panic(e)
+	}
+}
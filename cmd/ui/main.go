@@ -2,12 +2,14 @@ package main
 
 import (
     "../../pkg/dledger"
+    "../../pkg/hashgraph"
+    "context"
     "flag"
     "github.com/asticode/go-astikit"
     "github.com/asticode/go-astilectron"
     bootstrap "github.com/asticode/go-astilectron-bootstrap"
     "log"
-    "net"
+    "os"
     "sync"
     "time"
 )
@@ -16,13 +18,30 @@ var (
     w *astilectron.Window
 )
 
+// Events are sent to the window along with their state and hash, which is what the parent hashes of other events refer to
+type eventMessage struct {
+    *hashgraph.Event
+    hashgraph.EventState
+    Hash string `json:"hash"`
+}
+
 const (
-    defaultPort  = "8080" // Use this default port if it isn't specified via command line arguments.
-    updatePeriod = 50 * time.Millisecond
+    updatePeriod  = 50 * time.Millisecond
+    peersFilePath = "../dledger/peersBackupLOCAL.txt" // Local peers generated by keygen -demo, the other members are run with cmd/dledger
+    keyFilePath   = "../dledger/keys/Carol.key"       // Key generated by keygen -demo for the member listening on the default port in the local peers file
 )
 
 func main() {
-    flag.Parse()
+    // The member of the window is not persisted and does not print evaluations unless the settings say so
+    defaults := dledger.DefaultConfig()
+    defaults.PeersFile = peersFilePath
+    defaults.KeyFile = keyFilePath
+    defaults.DataDir = ""
+    defaults.EvaluationMode = false
+    config, err := dledger.ParseFlags(flag.CommandLine, os.Args[1:], defaults)
+    if err != nil {
+        log.Fatal(err)
+    }
 
     // Create logger
     l := log.New(log.Writer(), log.Prefix(), log.Flags())
@@ -57,59 +76,61 @@ func main() {
 
     mut.Lock()
 
-    port := defaultPort
-
-    localAddr := getLocalAddress()
-
-    peers := make(map[string]string)
-    peers[localAddr+":8080"] = "Ahmet"
-    peers[localAddr+":9090"] = "Erhan"
-    peers[localAddr+":7070"] = "Öznur"
-    peers[localAddr+":6060"] = "Waris"
-
-    _ = bootstrap.SendMessage(w, "peers", peers)
+    distributedLedger := dledger.NewDLedger(config)
+    peers := distributedLedger.PeerNames
 
-    distributedLedger := dledger.NewDLedgerFromPeers(port, peers)
+    _ = bootstrap.SendMessage(w, "peers", distributedLedger.PeerNames)
 
-    distributedLedger.WaitForPeers()
-    distributedLedger.Start()
+    _ = distributedLedger.WaitForPeers(context.Background()) // waits for a supermajority, the window is already open
+    distributedLedger.Start(context.Background())            // the member runs until the window is closed
+    dledger.NewLoadGenerator(distributedLedger).Start()      // keep the hashgraph busy with random transfers to show
 
     knownConsensusEvents := 0
-    knownHashgraphEvents := make(map[string]int, len(peers)+1)
-    firstRoundOfFameUndecided := make(map[string]uint32, len(peers)+1)
-    firstRoundOfFameUndecided[distributedLedger.MyAddress] = 0
-    for addr := range peers {
-        firstRoundOfFameUndecided[addr] = 0
+    knownHashgraphEvents := make(map[hashgraph.MemberID]int, len(peers))
+    firstRoundOfFameUndecided := make(map[hashgraph.MemberID]uint32, len(peers))
+    for id := range peers {
+        firstRoundOfFameUndecided[id] = 0
     }
 
     for {
         time.Sleep(updatePeriod)
         distributedLedger.Node.RWMutex.RLock()
-        for addr := range distributedLedger.Node.Hashgraph {
-            for _, event := range distributedLedger.Node.Hashgraph[addr][knownHashgraphEvents[addr]:] {
-                _ = bootstrap.SendMessage(w, "event", event)
+        for id := range distributedLedger.Node.Hashgraph {
+            // Known events are counted with the pruned ones, as pruning removes events from the start of the hashgraph
+            firstNewEventIndex := knownHashgraphEvents[id] - distributedLedger.Node.PrunedEventCount[id]
+            if firstNewEventIndex < 0 {
+                firstNewEventIndex = 0
+            }
+            for _, event := range distributedLedger.Node.Hashgraph[id][firstNewEventIndex:] {
+                _ = bootstrap.SendMessage(w, "event", eventMessage{event, event.State(), event.Hash()})
 
             }
-            knownHashgraphEvents[addr] = len(distributedLedger.Node.Hashgraph[addr])
+            knownHashgraphEvents[id] = distributedLedger.Node.PrunedEventCount[id] + len(distributedLedger.Node.Hashgraph[id])
+        }
+
+        // Known consensus events are counted with the pruned ones too, and start over if an installed snapshot has less
+        firstNewConsensusEventIndex := knownConsensusEvents - distributedLedger.Node.PrunedConsensusCount
+        if firstNewConsensusEventIndex < 0 || firstNewConsensusEventIndex > len(distributedLedger.Node.ConsensusEvents) {
+            firstNewConsensusEventIndex = 0
         }
 
-        for _, newConsensusEvent := range distributedLedger.Node.ConsensusEvents[knownConsensusEvents:] {
-            _ = bootstrap.SendMessage(w, "event", newConsensusEvent)
+        for _, newConsensusEvent := range distributedLedger.Node.ConsensusEvents[firstNewConsensusEventIndex:] {
+            _ = bootstrap.SendMessage(w, "event", eventMessage{newConsensusEvent, newConsensusEvent.State(), newConsensusEvent.Hash()})
         }
 
-        for addr, rofu := range distributedLedger.Node.FirstRoundOfFameUndecided {
-            if rofu > firstRoundOfFameUndecided[addr] {
-                for i := firstRoundOfFameUndecided[addr]; i < rofu; i++ {
-                    witness, ok := distributedLedger.Node.Witnesses[addr][i]
+        for id, rofu := range distributedLedger.Node.FirstRoundOfFameUndecided {
+            if rofu > firstRoundOfFameUndecided[id] {
+                for i := firstRoundOfFameUndecided[id]; i < rofu; i++ {
+                    witness, ok := distributedLedger.Node.Witnesses[id][i]
                     if ok {
-                        _ = bootstrap.SendMessage(w, "event", witness)
+                        _ = bootstrap.SendMessage(w, "event", eventMessage{witness, witness.State(), witness.Hash()})
                     }
                 }
-                firstRoundOfFameUndecided[addr] = rofu
+                firstRoundOfFameUndecided[id] = rofu
             }
         }
 
-        knownConsensusEvents = len(distributedLedger.Node.ConsensusEvents)
+        knownConsensusEvents = distributedLedger.Node.PrunedConsensusCount + len(distributedLedger.Node.ConsensusEvents)
         distributedLedger.Node.RWMutex.RUnlock()
     }
 
@@ -119,16 +140,6 @@ func handleMessages(_ *astilectron.Window, m bootstrap.MessageIn) (payload inter
     return
 }
 
-func getLocalAddress() string {
-    conn, err := net.Dial("udp", "eng.ku.edu.tr:80")
-    handleError(err)
-    defer func() {
-        handleError(conn.Close())
-    }()
-    localAddr := conn.LocalAddr().(*net.UDPAddr)
-    return localAddr.IP.String()
-}
-
 func handleError(e error) {
     if e != nil {
         panic(e)
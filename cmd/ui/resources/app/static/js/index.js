@@ -2,10 +2,10 @@ asticode.loader.init();
 asticode.modaler.init();
 asticode.notifier.init();
 
-// map of node signatures -> Kanva Group
-const nodeSignatureToVisualMap = new Map();
-const peerAddressToXMap = new Map();
-let peerAddressesToNamesObj;
+// map of event hashes -> Kanva Group
+const eventHashToVisualMap = new Map();
+const peerIDToXMap = new Map();
+let peerIDsToNamesObj;
 const transactionsDiv = document.getElementById("transactions");
 
 const stage = new Konva.Stage({
@@ -33,9 +33,9 @@ let stageHeight = stage.height();
 const margin = 40;
 
 const initCanvas = (peers) => {
-  peerAddressesToNamesObj = peers;
-  const peerAddressesToNames = Object.entries(peers);
-  const nodeCount = peerAddressesToNames.length;
+  peerIDsToNamesObj = peers;
+  const peerIDsToNames = Object.entries(peers);
+  const nodeCount = peerIDsToNames.length;
   const textSize = 12;
 
   const peerLinesLayer = new Konva.Layer();
@@ -47,7 +47,7 @@ const initCanvas = (peers) => {
     const peerText = new Konva.Text({
       x: margin + (lineSpacing + nodeLineWidth) * i,
       y: 0,
-      text: peerAddressesToNames[i][1],
+      text: peerIDsToNames[i][1],
       fontSize: textSize,
       fontFamily: "Calibri",
       fill: "black",
@@ -72,8 +72,8 @@ const initCanvas = (peers) => {
     peerLinesLayer.add(peerText);
     peerLinesLayer.add(peerLine);
 
-    peerAddressToXMap.set(
-      peerAddressesToNames[i][0],
+    peerIDToXMap.set(
+      peerIDsToNames[i][0],
       margin + (lineSpacing + nodeLineWidth) * i
     );
   }
@@ -117,6 +117,7 @@ const displayEvent = (event) => {
     Event is a JSON object
     string        `json:"owner"`
 	string        `json:"signature"`
+    string        `json:"hash"`
     string        `json:"self_parent_hash"`	
     string        `json:"other_parent_hash"`
     time.Time     `json:"timestamp"`        
@@ -129,13 +130,13 @@ const displayEvent = (event) => {
 	time.Time     `json:"consensus_timestamp"`
     time.Duration `json:"latency"`
     
-    Transaction is a JSON object
-    string  `json:"sender_address"`
-	string  `json:"receiver_address"`
-	float64 `json:"amount"`
+    Transaction is a base64 encoded JSON object of a transfer
+    string  `json:"sender_id"`
+	string  `json:"receiver_id"`
+	uint64  `json:"amount"`
     */
 
-  if (nodeSignatureToVisualMap.get(event.signature) === undefined) {
+  if (eventHashToVisualMap.get(event.hash) === undefined) {
     // we have a new event, visualize it
     if (
       event.self_parent_hash === undefined ||
@@ -143,7 +144,7 @@ const displayEvent = (event) => {
       event.self_parent_hash === ""
     ) {
       // it is an initial event, draw it at the bottom
-      const eventX = peerAddressToXMap.get(event.owner);
+      const eventX = peerIDToXMap.get(event.owner);
       const eventY = stageWidth - 40;
 
       const eventVisualGroup = new Konva.Group({
@@ -173,26 +174,26 @@ const displayEvent = (event) => {
 
       eventsLayer.add(eventVisualGroup);
       eventsLayer.draw();
-      nodeSignatureToVisualMap.set(event.signature, eventVisualGroup);
+      eventHashToVisualMap.set(event.hash, eventVisualGroup);
       return true;
     } else if (
-      nodeSignatureToVisualMap.get(event.self_parent_hash) === undefined ||
-      nodeSignatureToVisualMap.get(event.other_parent_hash) === undefined
+      eventHashToVisualMap.get(event.self_parent_hash) === undefined ||
+      eventHashToVisualMap.get(event.other_parent_hash) === undefined
     ) {
       // parents are not drawn yet, add to queue
       queuedEvents.push(event);
       return false;
     } else {
-      const eventX = peerAddressToXMap.get(event.owner);
+      const eventX = peerIDToXMap.get(event.owner);
       // find the parents location, calculate this ones location and draw
-      const selfParentY = nodeSignatureToVisualMap
+      const selfParentY = eventHashToVisualMap
         .get(event.self_parent_hash)
         .y();
 
-      const otherParentX = nodeSignatureToVisualMap
+      const otherParentX = eventHashToVisualMap
         .get(event.other_parent_hash)
         .x();
-      const otherParentY = nodeSignatureToVisualMap
+      const otherParentY = eventHashToVisualMap
         .get(event.other_parent_hash)
         .y();
       const eventY = Math.min(selfParentY - 45, otherParentY - 10);
@@ -239,22 +240,24 @@ const displayEvent = (event) => {
       eventsLayer.add(arrow);
       eventsLayer.add(eventVisualGroup);
       eventsLayer.draw();
-      nodeSignatureToVisualMap.set(event.signature, eventVisualGroup);
+      eventHashToVisualMap.set(event.hash, eventVisualGroup);
       return true;
     }
   } else {
     // we already visualized this event, update its state
-    const visual = nodeSignatureToVisualMap.get(event.signature);
+    const visual = eventHashToVisualMap.get(event.hash);
     visual.children[0].fill(calculateColor(event)); // circle
 
     if (event.transactions !== undefined && event.transactions !== null) {
       event.transactions.forEach((transaction) => {
+        // transactions are base64 encoded transfers
+        const transfer = JSON.parse(atob(transaction));
         const text = document.createTextNode(
-          peerAddressesToNamesObj[transaction.sender_address] +
+          peerIDsToNamesObj[transfer.sender_id] +
             " -> " +
-            peerAddressesToNamesObj[transaction.receiver_address] +
+            peerIDsToNamesObj[transfer.receiver_id] +
             ": " +
-            transaction.amount.toFixed(2)
+            transfer.amount
         );
         transactionsDiv.appendChild(text);
         transactionsDiv.appendChild(document.createElement("br"));
@@ -0,0 +1,40 @@
+package dledger
+
+import (
+	"sync"
+
+	"../hashgraph"
+)
+
+//AddressBook : Maps the IDs of members to the network addresses they are reachable at. Members are identified by their
+// IDs in the hashgraph, so the address of a member can be updated while the ledger is running.
+type AddressBook struct {
+	sync.RWMutex
+	addresses map[hashgraph.MemberID]string // map of member ID -> ip:port of that member
+}
+
+//NewAddressBook : Creates an address book from a map of member IDs to addresses
+func NewAddressBook(addresses map[hashgraph.MemberID]string) *AddressBook {
+	ab := &AddressBook{addresses: make(map[hashgraph.MemberID]string, len(addresses))}
+	for id, address := range addresses {
+		ab.addresses[id] = address
+	}
+	return ab
+}
+
+//Address : Returns the address of the member with the given ID, ok is false if the member has no address
+func (ab *AddressBook) Address(id hashgraph.MemberID) (string, bool) {
+	ab.RLock()
+	defer ab.RUnlock()
+
+	address, ok := ab.addresses[id]
+	return address, ok
+}
+
+//SetAddress : Updates the address of the member with the given ID, gossip connects to the new address from now on
+func (ab *AddressBook) SetAddress(id hashgraph.MemberID, address string) {
+	ab.Lock()
+	defer ab.Unlock()
+
+	ab.addresses[id] = address
+}
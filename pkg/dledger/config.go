@@ -0,0 +1,178 @@
+package dledger
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+//Config : Settings of a member. Settings are read from a YAML or TOML file and overridden by command line flags, the
+// keys of the file are the names of the flags with underscores, e.g. gossip_interval for -gossip-interval.
+type Config struct {
+	ListenAddress       string        `yaml:"listen_address" toml:"listen_address"`             // address that my server listens on, e.g. ":8080"
+	AdvertisedAddress   string        `yaml:"advertised_address" toml:"advertised_address"`     // address that my peers reach me at, the listen address if empty, or my local IP address and the listen port if I listen on all interfaces
+	PeersFile           string        `yaml:"peers_file" toml:"peers_file"`                     // path of the peers file
+	KeyFile             string        `yaml:"key_file" toml:"key_file"`                         // path of the file of my private key
+	DataDir             string        `yaml:"data_dir" toml:"data_dir"`                         // directory that my events are stored in, events are not persisted if empty
+	CertFile            string        `yaml:"cert_file" toml:"cert_file"`                       // path of my TLS certificate, connections are plaintext if empty
+	CertKeyFile         string        `yaml:"cert_key_file" toml:"cert_key_file"`               // path of the private key of my TLS certificate
+	CAFile              string        `yaml:"ca_file" toml:"ca_file"`                           // path of the CAs that sign the certificates of the members, every certificate is pinned in the peers file if empty
+	GossipInterval      time.Duration `yaml:"gossip_interval" toml:"gossip_interval"`           // the amount of time between each random gossip
+	LogLevel            string        `yaml:"log_level" toml:"log_level"`                       // lowest level of the logs that are printed: debug, info, warn or error
+	EvaluationMode      bool          `yaml:"evaluation" toml:"evaluation"`                     // performance metrics are measured and printed in evaluation mode
+	EvaluationInterval  int           `yaml:"evaluation_interval" toml:"evaluation_interval"`   // after per this many gossips, print out evaluations
+	EvaluationMilestone int           `yaml:"evaluation_milestone" toml:"evaluation_milestone"` // print out evaluations once when I know this many events, never if 0
+	LoadRate            float64       `yaml:"load_rate" toml:"load_rate"`                       // random transfers generated per second, none if 0
+}
+
+//DefaultConfig : Returns the settings of a member that listens on port 8080 and reads its peers and key from the
+// working directory, which is where cmd/dledger keeps them
+func DefaultConfig() Config {
+	return Config{
+		ListenAddress:       ":8080",
+		PeersFile:           "peers.txt",
+		KeyFile:             "member.key",
+		DataDir:             ".",
+		GossipInterval:      100 * time.Millisecond,
+		LogLevel:            "info",
+		EvaluationMode:      true,
+		EvaluationInterval:  20,
+		EvaluationMilestone: 5000,
+	}
+}
+
+//LoadConfig : Reads the settings from a YAML file, or a TOML file if its extension is .toml. Settings that are not in
+// the file keep their values in defaults. Unknown keys are an error, so that a misspelled setting is not ignored.
+func LoadConfig(path string, defaults Config) (Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return defaults, err
+	}
+	config := defaults
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		metadata, err := toml.Decode(string(content), &config)
+		if err != nil {
+			return defaults, err
+		}
+		if undecoded := metadata.Undecoded(); len(undecoded) > 0 {
+			return defaults, fmt.Errorf("unknown setting %s in %s", undecoded[0], path)
+		}
+	default:
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+			return defaults, fmt.Errorf("%s: %s", path, err.Error())
+		}
+	}
+	return config, nil
+}
+
+//ParseFlags : Parses the command line flags of the settings from args. Settings are taken from the flags, then from
+// the file given by the -config flag, then from defaults.
+func ParseFlags(flags *flag.FlagSet, args []string, defaults Config) (Config, error) {
+	config := defaults
+	configPath := flags.String("config", "", "YAML or TOML `file` of the settings, flags override the settings in it")
+	flags.StringVar(&config.ListenAddress, "listen", config.ListenAddress, "`address` that the server listens on")
+	flags.StringVar(&config.AdvertisedAddress, "advertise", config.AdvertisedAddress, "`address` that the peers reach this member at (default the listen address, or the local IP address and the listen port if it listens on all interfaces)")
+	flags.StringVar(&config.PeersFile, "peers", config.PeersFile, "peers `file`")
+	flags.StringVar(&config.KeyFile, "key", config.KeyFile, "`file` of the private key of this member")
+	flags.StringVar(&config.DataDir, "data-dir", config.DataDir, "`directory` that the events are stored in, events are not persisted if empty")
+	flags.StringVar(&config.CertFile, "cert", config.CertFile, "TLS certificate `file`, connections are plaintext if empty")
+	flags.StringVar(&config.CertKeyFile, "cert-key", config.CertKeyFile, "`file` of the private key of the TLS certificate")
+	flags.StringVar(&config.CAFile, "ca", config.CAFile, "`file` of the CAs that sign the certificates of the members")
+	flags.DurationVar(&config.GossipInterval, "gossip-interval", config.GossipInterval, "time between each random gossip")
+	flags.StringVar(&config.LogLevel, "log-level", config.LogLevel, "lowest `level` of the printed logs: debug, info, warn or error")
+	flags.BoolVar(&config.EvaluationMode, "evaluation", config.EvaluationMode, "measure and print performance metrics")
+	flags.IntVar(&config.EvaluationInterval, "evaluation-interval", config.EvaluationInterval, "print performance metrics after every this many gossips")
+	flags.IntVar(&config.EvaluationMilestone, "evaluation-milestone", config.EvaluationMilestone, "print performance metrics once when this many events are known, never if 0")
+	flags.Float64Var(&config.LoadRate, "load-rate", config.LoadRate, "random transfers generated per second")
+	if err := flags.Parse(args); err != nil {
+		return defaults, err
+	}
+	if *configPath == "" {
+		return config, config.Validate()
+	}
+
+	// Read the file over the defaults, then set the flags that are given again, so that they override the file
+	given := make(map[string]string)
+	flags.Visit(func(f *flag.Flag) {
+		given[f.Name] = f.Value.String()
+	})
+	fileConfig, err := LoadConfig(*configPath, defaults)
+	if err != nil {
+		return defaults, err
+	}
+	config = fileConfig
+	for name, value := range given {
+		if err := flags.Set(name, value); err != nil {
+			return defaults, err
+		}
+	}
+	return config, config.Validate()
+}
+
+//Validate : Returns an error if a setting is missing or out of range
+func (c Config) Validate() error {
+	if c.ListenAddress == "" {
+		return errors.New("listen address is missing")
+	}
+	if c.GossipInterval <= 0 {
+		return fmt.Errorf("gossip interval %s must be positive", c.GossipInterval)
+	}
+	if c.EvaluationMode && c.EvaluationInterval <= 0 {
+		return fmt.Errorf("evaluation interval %d must be positive", c.EvaluationInterval)
+	}
+	if c.EvaluationMilestone < 0 {
+		return fmt.Errorf("evaluation milestone %d must not be negative", c.EvaluationMilestone)
+	}
+	if c.LoadRate < 0 {
+		return fmt.Errorf("load rate %f must not be negative", c.LoadRate)
+	}
+	if (c.CertFile == "") != (c.CertKeyFile == "") {
+		return errors.New("TLS certificate and its key must be given together")
+	}
+	_, err := c.logLevel()
+	return err
+}
+
+// Returns the path of the file that my events are stored in, empty if they are not persisted. The file is named after
+// the listen port, so that members on the same device can share a data directory.
+func (c Config) storePath() string {
+	if c.DataDir == "" {
+		return ""
+	}
+	name := c.ListenAddress
+	if _, port, err := net.SplitHostPort(c.ListenAddress); err == nil {
+		name = port
+	}
+	return filepath.Join(c.DataDir, "events_"+name+".log")
+}
+
+// Returns the level of the log level setting
+func (c Config) logLevel() (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.LogLevel)); err != nil {
+		return level, fmt.Errorf("log level %q is not one of debug, info, warn or error", c.LogLevel)
+	}
+	return level, nil
+}
+
+// Returns the logger that prints the logs of the member at or above the log level
+func (c Config) logger() *slog.Logger {
+	level, err := c.logLevel()
+	handleError(err)
+	return slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+}
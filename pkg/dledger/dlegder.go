@@ -2,131 +2,409 @@ package dledger
 
 import (
 	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"math/rand"
+	"io/ioutil"
+	"log/slog"
 	"net"
-	"net/rpc"
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"google.golang.org/grpc"
+
 	"../hashgraph"
-	uuid "github.com/satori/go.uuid"
+	"../wire"
 )
 
 const (
-	evaluationMode             = true                   // flag to indicate evaluation mode. Performance metrics are measured and printed in evaluation mode.
-	gossipWaitTime             = 100 * time.Millisecond // the amount of time.sleep milliseconds between each random gossip
-	connectionAttemptDelayTime = 100 * time.Millisecond // the amount of time.sleep milliseconds between each connection attempt
-	printPerMrpcCall           = 20                     // After per this many RPC calls, print out evaluations
+	connectionAttemptDelayTime = 100 * time.Millisecond // time between the attempts to reach the peers that are not online yet
+	gossipCallTimeout          = 5 * time.Second        // a gossip fails if a peer does not reply to one of its calls in this long
+	flushTimeout               = 5 * time.Second        // how long a stopping member tries to flush its buffered transactions to its peers
+	defaultStake               = 1                      // stake of a member if it isn't specified in the peers file
+	defaultBalance             = 10000                  // genesis balance of a member if it isn't specified in the peers file
+	loopbackAddress            = "127.0.0.1"            // local address of a device that is not on a network
 )
 
 //DLedger : Struct for a member of the distributed ledger
 type DLedger struct {
-	Node           *hashgraph.Node
-	MyAddress      string
-	PeerAddresses  []string
-	PeerAddressMap map[string]string
+	Node          *hashgraph.Node
+	Ledger        *Ledger
+	Tracker       *Tracker // application of the node, which applies transactions to the ledger and tracks the submitted ones
+	MyID          hashgraph.MemberID
+	MyAddress     string
+	PeerIDs       []hashgraph.MemberID          // IDs of the other members
+	PeerNames     map[hashgraph.MemberID]string // human readable names of all members, including me
+	AddressBook   *AddressBook
+	config        Config               // settings that I was initialized with
+	logger        *slog.Logger         // prints my logs at or above the log level of my settings
+	transport     Transport            // carries the connections to my peers
+	authenticator *memberAuthenticator // authenticates the connections to and from my peers, nil if they are plaintext
+	peers         *peerSet             // connections to my peers and their health
+	privateKey    ed25519.PrivateKey   // key that my transfers are signed with
+	lastNonce     uint64               // nonce of the last transfer that I signed, guarded by the lock of the node
+	server        *grpc.Server         // serves the calls of my peers and clients until I stop
+	cancel        context.CancelFunc   // stops the gossip routine, nil until I start
+	gossipDone    chan struct{}        // closed when the gossip routine returns
+	stopOnce      sync.Once            // stops me only once
+	stopErr       error                // result of Stop, returned by every call of Stop
+	stopped       chan struct{}        // closed when I stop
+}
+
+//Peer : A member of the distributed ledger as it is listed in the peers file
+type Peer struct {
+	Address         string            // ip:port that the member is reachable at
+	Name            string            // human readable name of the member
+	PublicKey       ed25519.PublicKey // key that the member signs its events with
+	Stake           uint64            // weight of the member in consensus decisions
+	Balance         uint64            // balance of the member in the genesis of the ledger
+	CertFingerprint string            // hex encoded SHA-256 fingerprint of the TLS certificate of the member, empty if its certificate is signed by a CA
 }
 
-func NewDLedgerFromPeers(port string, peerAddressMap map[string]string) *DLedger {
-	localIPAddress := getLocalAddress()
-	myAddress := localIPAddress + ":" + port
-	// Assert that your own address is on the peers file
-	_, ok := peerAddressMap[myAddress]
-	if !ok {
-		panic("Peers file does not include my address: " + myAddress)
-	}
-
-	// Copy peer addresses to a slice for random access during gossip
-	peerAddresses := make([]string, len(peerAddressMap)-1)
-	i := 0
-	for k := range peerAddressMap {
-		if k != myAddress {
-			peerAddresses[i] = k // suggestion #1: do not add my own ip:port here, instead of creating another slice without me later
-			i++
+//NewDLedgerFromPeers : Initialize a member from a map of member IDs to peers, signing events with the given private key.
+// The member is found in the peers by its key and listens on the listen address of the config on the transport. Its
+// peers reach it at the advertised address of the config, or at the listen address if none is advertised, which may
+// differ from its address in the peers. Connections between members are authenticated with mutual TLS unless
+// tlsConfig is nil. Events are persisted to the data directory of the config and recovered from it on a restart,
+// unless the data directory is empty. Files named in the config are not read, NewDLedger reads them.
+func NewDLedgerFromPeers(config Config, peers map[hashgraph.MemberID]Peer, privateKey ed25519.PrivateKey, transport Transport, tlsConfig *TLSConfig) *DLedger {
+	handleError(config.Validate())
+	myAddress := config.AdvertisedAddress
+	if myAddress == "" {
+		myAddress = config.ListenAddress
+	}
+
+	// Assert that the private key belongs to a member, otherwise nobody would accept my events
+	myID := hashgraph.NewMemberID(privateKey.Public().(ed25519.PublicKey))
+	if _, ok := peers[myID]; !ok {
+		panic("Peers file does not include my public key: " + string(myID))
+	}
+
+	peerNames := make(map[hashgraph.MemberID]string, len(peers))
+	addresses := make(map[hashgraph.MemberID]string, len(peers))
+	stakes := make(map[hashgraph.MemberID]uint64, len(peers))
+	genesis := make(map[hashgraph.MemberID]uint64, len(peers))
+	for id, peer := range peers {
+		peerNames[id] = peer.Name
+		addresses[id] = peer.Address
+		stakes[id] = peer.Stake
+		genesis[id] = peer.Balance
+	}
+	addresses[myID] = myAddress
+
+	// Copy peer IDs to a slice for random access during gossip
+	peerIDs := make([]hashgraph.MemberID, 0, len(peers)-1)
+	for id := range peers {
+		if id != myID {
+			peerIDs = append(peerIDs, id)
 		}
 	}
 
 	// Setup the Hashgraph
-	signatureUUID, err := uuid.NewV4()
+	initialHashgraph := make(map[hashgraph.MemberID][]*hashgraph.Event, len(peers))
+	for id := range peers {
+		initialHashgraph[id] = make([]*hashgraph.Event, 0) // We should not know any event other than our own event at the start
+	}
+	myNode := hashgraph.NewNode(initialHashgraph, privateKey, stakes)
+	ledger := NewLedger(genesis)
+	tracker := NewTracker(ledger)
+	myNode.Application = tracker
+
+	for id := range myNode.Hashgraph {
+		myNode.Witnesses[id] = make(map[uint32]*hashgraph.Event)
+		myNode.FirstEventOfNotConsensusIndex[id] = 0 // index 0 for the initial event
+
+	}
+
+	// Recover the events I knew before I was restarted
+	if storePath := config.storePath(); storePath != "" {
+		handleError(os.MkdirAll(config.DataDir, 0755))
+		store, err := hashgraph.NewFileEventStore(storePath)
+		handleError(err)
+		myNode.Store = store
+		handleError(myNode.Recover())
+	}
+	if len(myNode.Hashgraph[myID]) == 0 {
+		handleError(myNode.CreateInitialEvent())
+	}
+
+	dl := &DLedger{
+		Node:          myNode,
+		Ledger:        ledger,
+		Tracker:       tracker,
+		MyID:          myID,
+		MyAddress:     myAddress,
+		PeerIDs:       peerIDs,
+		PeerNames:     peerNames,
+		AddressBook:   NewAddressBook(addresses),
+		config:        config,
+		logger:        config.logger(),
+		transport:     transport,
+		authenticator: newMemberAuthenticator(tlsConfig, peers),
+		peers:         newPeerSet(peerIDs, peerNames),
+		privateKey:    privateKey,
+		stopped:       make(chan struct{}),
+	}
+	dl.lastNonce = dl.recoverLastNonce()
+
+	// Setup the server, peers call the gossip service of the node and clients call the transaction service. Only members
+	// can gossip, clients do not need a certificate. Every member has its own server, so that many members can run in
+	// one process.
+	server := grpc.NewServer(grpc.Creds(dl.authenticator.serverCredentials()), grpc.UnaryInterceptor(dl.authenticator.authorizeGossip))
+	wire.RegisterGossipServer(server, &gossipService{node: myNode, authenticator: dl.authenticator, onStoreFailure: dl.stopOnStoreFailure})
+	wire.RegisterTransactionsServer(server, &TransactionService{dl: dl})
+	listener, err := transport.Listen(config.ListenAddress)
 	handleError(err)
-	signature := signatureUUID.String()
+	go server.Serve(listener) // returns when the server is stopped
+	dl.server = server
+
+	return dl
+}
+
+//NewDLedger : Initialize a member in the distributed ledger from the peers file, key file and TLS certificate named in
+// the config, connecting over TCP. Unless an address is advertised, the peers reach the member at its listen address,
+// or at the IP address of one of its network interfaces if it listens on all of them.
+// This is not adding a new member, but rather reading a member from a list and initializing it.
+func NewDLedger(config Config) *DLedger {
+	handleError(config.Validate())
+	if config.AdvertisedAddress == "" {
+		var err error
+		config.AdvertisedAddress, err = defaultAdvertisedAddress(config.ListenAddress)
+		handleError(err)
+	}
+	localHost, _, err := net.SplitHostPort(config.AdvertisedAddress)
 	handleError(err)
-	initialHashgraph := make(map[string][]*hashgraph.Event, len(peerAddressMap))
-	for addr := range peerAddressMap {
-		initialHashgraph[addr] = make([]*hashgraph.Event, 0) // We should not know any event other than our own event at the start
-	}
-	initialEvent := hashgraph.Event{
-		Owner:              myAddress,
-		Signature:          signature,
-		SelfParentHash:     "",
-		OtherParentHash:    "",
-		Timestamp:          time.Now(),
-		Transactions:       nil,
-		Round:              1,
-		IsWitness:          true, // true because the initial event is the first event of its round
-		IsFamous:           false,
-		IsFameDecided:      false,
-		RoundReceived:      0,
-		ConsensusTimestamp: time.Unix(0, 0),
-	}
-	initialHashgraph[myAddress] = append(initialHashgraph[myAddress], &initialEvent)
-	myNode := hashgraph.NewNode(initialHashgraph, myAddress)
-
-	for addr := range myNode.Hashgraph {
-		myNode.Witnesses[addr] = make(map[uint32]*hashgraph.Event)
-		myNode.FirstEventOfNotConsensusIndex[addr] = 0 // index 0 for the initial event
-
-	}
-	myNode.Witnesses[initialEvent.Owner][1] = &initialEvent
-	myNode.Events[initialEvent.Signature] = &initialEvent
-	myNode.FirstRoundOfFameUndecided[initialEvent.Owner] = 1
-
-	// Setup the server
-	_ = rpc.Register(myNode)
-	tcpAddr, _ := net.ResolveTCPAddr("tcp", myAddress)
-	listener, _ := net.ListenTCP("tcp", tcpAddr)
-	go listenForRPCConnections(listener)
-
-	return &DLedger{
-		Node:           myNode,
-		MyAddress:      myAddress,
-		PeerAddresses:  peerAddresses,
-		PeerAddressMap: peerAddressMap,
+	peers := ReadPeers(config.PeersFile, localHost) // localhost in the peers file is the host that I advertise
+	privateKey := ReadPrivateKey(config.KeyFile)
+	var tlsConfig *TLSConfig
+	if config.CertFile != "" {
+		var err error
+		tlsConfig, err = LoadTLSConfig(config.CertFile, config.CertKeyFile, config.CAFile)
+		handleError(err)
 	}
+	return NewDLedgerFromPeers(config, peers, privateKey, TCPTransport{}, tlsConfig)
 }
 
-//NewDLedger : Initialize a member in the distributed ledger.
-// This is not adding a new member, but rather reading a member from a list and initializing it.
-func NewDLedger(port string, peersFilePath string) *DLedger {
-	localIPAddress := getLocalAddress()
-	peerAddressMap := readPeerAddresses(peersFilePath, localIPAddress)
-	return NewDLedgerFromPeers(port, peerAddressMap)
+//Start : Starts the gossip routine in a go routine, which gossips until the context is cancelled or Stop is called.
+// Cancelling the context stops the member like Stop does. Start must be called at most once.
+func (dl *DLedger) Start(ctx context.Context) {
+	if dl.cancel != nil {
+		return
+	}
+	ctx, dl.cancel = context.WithCancel(ctx)
+	dl.gossipDone = make(chan struct{})
+	go func() {
+		defer close(dl.gossipDone)
+		dl.gossipRoutine(ctx)
+	}()
+	go func() {
+		<-ctx.Done()
+		_ = dl.Stop()
+	}()
+}
+
+//Stop : Stops the member: stops gossiping, flushes the transactions in my buffer to my peers, then closes my server,
+// my connections and my store. Returns an error if the buffered transactions could not be flushed, they are lost
+// unless their senders submit them again. Calling Stop again returns the same result without doing anything.
+func (dl *DLedger) Stop() error {
+	dl.stopOnce.Do(func() {
+		if dl.cancel != nil {
+			dl.cancel()
+			<-dl.gossipDone
+		}
+		dl.stopErr = dl.flush()
+		dl.server.GracefulStop() // waits for the calls in progress, which may insert events
+		dl.peers.close()
+		if err := dl.Node.CloseStore(); err != nil && dl.stopErr == nil {
+			dl.stopErr = err
+		}
+		close(dl.stopped)
+	})
+	return dl.stopErr
 }
 
-//Start : Starts the gossip routine in a go routine.
-func (dl *DLedger) Start() {
-	go gossipRoutine(dl.Node, dl.PeerAddresses)
+//Done : Returns a channel that is closed when I stop, either by Stop or by myself when my store fails
+func (dl *DLedger) Done() <-chan struct{} {
+	return dl.stopped
 }
 
-//PerformTransaction : Adds a transaction to the member's buffer.
-func (dl *DLedger) PerformTransaction(receiverAddr string, amount float64) {
+// Stops me if the error is a failure of my store. My hashgraph can not be persisted anymore, so I would fork after a
+// restart if my peers learned my later events.
+func (dl *DLedger) stopOnStoreFailure(err error) {
+	if errors.Is(err, hashgraph.ErrStoreFailed) {
+		dl.logger.Error("Stopping, my store failed", "error", err)
+		go func() {
+			_ = dl.Stop() // waits for the call in progress that failed
+		}()
+	}
+}
+
+// Makes sure that my peers learn the transactions submitted to me before I stop. Transactions in my buffer are added
+// to my next event when a peer gossips to me, so I wait for my buffer to empty, then push my events to a peer. Gives up
+// after flushTimeout.
+func (dl *DLedger) flush() error {
+	deadline := time.Now().Add(flushTimeout)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+
+	if err := dl.Node.StoreError(); err != nil {
+		return err // my events are not persisted, my peers must not learn them
+	}
+
+	// My latest event may not be pushed yet if it has transactions, events without transactions can be lost
+	dl.Node.RWMutex.RLock()
+	myEvents := dl.Node.Hashgraph[dl.MyID]
+	pending := len(dl.Node.TransactionBuffer) > 0 || len(myEvents) > 0 && len(myEvents[len(myEvents)-1].Transactions) > 0
+	dl.Node.RWMutex.RUnlock()
+	if !pending {
+		return nil
+	}
+
+	for {
+		dl.Node.RWMutex.RLock()
+		buffered := len(dl.Node.TransactionBuffer)
+		dl.Node.RWMutex.RUnlock()
+		if buffered == 0 {
+			break
+		}
+		if !sleep(ctx, dl.config.GossipInterval) {
+			return fmt.Errorf("%d buffered transactions are not added to an event", buffered)
+		}
+	}
+	for {
+		if peerID, ok := dl.peers.choose(time.Now()); ok {
+			if err := dl.gossipOnce(ctx, peerID); err == nil {
+				return nil
+			}
+		}
+		if !sleep(ctx, dl.config.GossipInterval) {
+			return errors.New("events with my buffered transactions are not pushed to a peer")
+		}
+	}
+}
+
+//PeerHealth : Returns how reachable each peer is for my gossip, in the order of PeerIDs
+func (dl *DLedger) PeerHealth() []PeerHealth {
+	return dl.peers.health()
+}
+
+//PerformTransaction : Signs a transfer from me with my next nonce and submits it, returns the ID of the transaction
+func (dl *DLedger) PerformTransaction(receiverID hashgraph.MemberID, amount uint64) (string, error) {
 	dl.Node.RWMutex.Lock()
-	dl.Node.TransactionBuffer = append(dl.Node.TransactionBuffer, hashgraph.Transaction{
-		SenderAddress:   dl.MyAddress,
-		ReceiverAddress: receiverAddr,
-		Amount:          amount,
-	})
-	dl.Node.RWMutex.Unlock()
+	defer dl.Node.RWMutex.Unlock()
+
+	transfer := Transfer{
+		SenderID:   dl.MyID,
+		ReceiverID: receiverID,
+		Amount:     amount,
+		Nonce:      dl.nextNonce(),
+	}
+	transfer.Sign(dl.privateKey)
+	id, err := dl.submitTransfer(transfer)
+	if err != nil {
+		dl.lastNonce-- // the nonce is not used, my next transfer can take it
+	}
+	return id, err
+}
+
+//SubmitTransfer : Adds a transfer signed by its sender to the member's buffer, returns the ID of the transaction that
+// its status can be queried with. Fails if the committed balance of the sender is not enough or the nonce is already
+// used, though the transfer may still be rejected at consensus if earlier transfers of the sender spend the balance or
+// the nonce first.
+func (dl *DLedger) SubmitTransfer(transfer Transfer) (string, error) {
+	dl.Node.RWMutex.Lock()
+	defer dl.Node.RWMutex.Unlock()
+
+	return dl.submitTransfer(transfer)
+}
+
+// Adds the transfer to the buffer of the node, the node must be locked
+func (dl *DLedger) submitTransfer(transfer Transfer) (string, error) {
+	if transfer.Amount == 0 {
+		return "", errors.New("amount of the transfer must be positive")
+	}
+	if !transfer.VerifySignature() {
+		return "", errors.New("transfer is not signed by its sender")
+	}
+	if _, ok := dl.Ledger.Balance(transfer.ReceiverID); !ok {
+		return "", fmt.Errorf("receiver %s is not a member", transfer.ReceiverID)
+	}
+	if balance, ok := dl.Ledger.Balance(transfer.SenderID); !ok || balance < transfer.Amount {
+		return "", fmt.Errorf("balance %d is not enough to transfer %d", balance, transfer.Amount)
+	}
+	if lastNonce := dl.Ledger.Nonce(transfer.SenderID); transfer.Nonce <= lastNonce {
+		return "", fmt.Errorf("nonce %d is already used, last nonce of %s is %d", transfer.Nonce, transfer.SenderID, lastNonce)
+	}
+
+	tx := transfer.Encode()
+	id := TransactionID(tx)
+	if !dl.Tracker.track(id) {
+		return "", fmt.Errorf("transaction %s is already submitted", id)
+	}
+	dl.Node.TransactionBuffer = append(dl.Node.TransactionBuffer, tx)
+	return id, nil
+}
+
+// Returns the nonce for my next transfer, the node must be locked. Clients that sign with my key may have used some
+// nonces, so my nonces continue from the nonces in the ledger if they are ahead.
+func (dl *DLedger) nextNonce() uint64 {
+	dl.lastNonce = max(dl.lastNonce, dl.Ledger.Nonce(dl.MyID)) + 1
+	return dl.lastNonce
+}
+
+// Returns the nonce of the last transfer that I signed before I was restarted. My transfers that did not reach
+// consensus yet are in my own events.
+func (dl *DLedger) recoverLastNonce() uint64 {
+	lastNonce := dl.Ledger.Nonce(dl.MyID)
+	for _, e := range dl.Node.Hashgraph[dl.MyID] {
+		for _, tx := range e.Transactions {
+			if transfer, err := DecodeTransfer(tx); err == nil && transfer.SenderID == dl.MyID {
+				lastNonce = max(lastNonce, transfer.Nonce)
+			}
+		}
+	}
+	return lastNonce
+}
+
+//TransactionStatus : Returns the status of a transaction that was submitted to me
+func (dl *DLedger) TransactionStatus(id string) TransactionStatus {
+	dl.Node.RWMutex.RLock()
+	defer dl.Node.RWMutex.RUnlock()
+
+	status, ok := dl.Tracker.status(id)
+	if !ok || status.State == TxConsensus {
+		return status
+	}
+	for _, tx := range dl.Node.TransactionBuffer {
+		if TransactionID(tx) == id {
+			return status
+		}
+	}
+	// Transactions are only added to my own events, latest events are the most likely ones to include it
+	myEvents := dl.Node.Hashgraph[dl.MyID]
+	for i := len(myEvents) - 1; i >= 0; i-- {
+		for _, tx := range myEvents[i].Transactions {
+			if TransactionID(tx) == id {
+				status.State = TxInEvent
+				status.EventHash = myEvents[i].Hash()
+				return status
+			}
+		}
+	}
+	return status
 }
 
 func createEvaluationString(node *hashgraph.Node, rpcCallsSoFar int, startOfGossip time.Time) string {
 	// How long have I been gossipping
 	gossipDuration := float64(time.Now().Sub(startOfGossip).Milliseconds()) / 1000.0
 
-	// What is the average latency among them
+	// What is the average latency among the consensus events that are not pruned
 	latencyTotal := int64(0)
 	for _, e := range node.ConsensusEvents {
 		latencyTotal += e.Latency.Milliseconds()
@@ -135,8 +413,8 @@ func createEvaluationString(node *hashgraph.Node, rpcCallsSoFar int, startOfGoss
 
 	// How many events are there in total
 	numEvents := 0
-	for addr := range node.Hashgraph {
-		numEvents += len(node.Hashgraph[addr])
+	for id := range node.Hashgraph {
+		numEvents += node.PrunedEventCount[id] + len(node.Hashgraph[id])
 	}
 
 	str := "\n#### EVAL ####" +
@@ -145,160 +423,309 @@ func createEvaluationString(node *hashgraph.Node, rpcCallsSoFar int, startOfGoss
 		"\n\tAvg. Gossip/sec:" + strconv.FormatFloat(float64(rpcCallsSoFar)/gossipDuration, 'f', 5, 64) +
 		"\n\tAvg. Latency: " + strconv.FormatFloat(latencyAvg, 'f', 5, 64) + " (sec)" +
 		"\n\tNum. of Events: " + strconv.Itoa(numEvents) +
-		"\n\tNum. of Consensus Events: " + strconv.Itoa(len(node.ConsensusEvents)) +
+		"\n\tNum. of Consensus Events: " + strconv.Itoa(node.PrunedConsensusCount+len(node.ConsensusEvents)) +
 		"\n#### EVAL ####\n"
 	return str
 
 }
 
-// Infinite loop of gossip routine, each gossip delayed by a constant time.
-func gossipRoutine(node *hashgraph.Node, peerAddresses []string) {
-	// Get RPC clients /* V2 all together */
-
-	peerClientMap := make(map[string]*rpc.Client, len(peerAddresses))
-	for _, addr := range peerAddresses {
-		peerRPCConnection, err := rpc.Dial("tcp", addr)
-		handleError(err)
-		peerClientMap[addr] = peerRPCConnection
-	}
-
-	defer func() {
-		for _, peerRPCConnection := range peerClientMap {
-			_ = peerRPCConnection.Close()
-		}
-	}()
+// Loop of gossip routine until the context is cancelled, each gossip delayed by the gossip interval of my settings. A
+// failed gossip does not stop the routine, the peer is skipped for a while and the routine gossips with the other
+// peers, so I keep reaching consensus as long as a supermajority is alive.
+func (dl *DLedger) gossipRoutine(ctx context.Context) {
+	defer dl.peers.close()
 
 	// Start gossip
+	node := dl.Node
 	c := 0
 	startOfGossip := time.Now()
-	var err error
 	eventEvaluationMilestonReached := false
-	for {
-		// Choose a peer
-		randomPeerConnection := peerClientMap[peerAddresses[rand.Intn(len(peerAddresses))]] /* V2 */
-		//randomPeer := peerAddresses[rand.Intn(len(peerAddresses))] /* V1 */
-
-		// Calculate how many events I know
-		knownEventNums := make(map[string]int, len(node.Hashgraph))
+	for sleep(ctx, dl.config.GossipInterval) {
+		// Choose a peer that is not skipped after a failure
+		randomPeerID, ok := dl.peers.choose(time.Now())
+		if !ok {
+			continue
+		}
+		if dl.gossipOnce(ctx, randomPeerID) != nil {
+			continue
+		}
 
 		node.RWMutex.RLock()
 
+		// Calculate how many events I know, including the pruned ones
 		numEvents := 0
-		for addr := range node.Hashgraph {
-			knownEventNums[addr] = len(node.Hashgraph[addr])
-			numEvents += knownEventNums[addr]
+		for id := range node.Hashgraph {
+			numEvents += node.PrunedEventCount[id] + len(node.Hashgraph[id])
 		}
 
-		if evaluationMode && numEvents >= 5000 && !eventEvaluationMilestonReached {
+		milestone := dl.config.EvaluationMilestone
+		if dl.config.EvaluationMode && milestone > 0 && numEvents >= milestone && !eventEvaluationMilestonReached {
 			eventEvaluationMilestonReached = true
 			evalString := createEvaluationString(node, c, startOfGossip)
 			fmt.Println(evalString)
 		}
 
-		// Ask the chosen peer how many events they do not know but I know
-		numEventsToSend := make(map[string]int, len(node.Hashgraph))
-		//peerRPCconn, err := rpc.Dial("tcp", randomPeer)                                         /* V1 */
-		//handleError(err)                                                                        /* V1 */
-		//_ = peerRPCconn.Call("Node.GetNumberOfMissingEvents", knownEventNums, &numEventsToSend) /* V1 */
-		err = randomPeerConnection.Call("Node.GetNumberOfMissingEvents", knownEventNums, &numEventsToSend) /* V2 */
-		handleError(err)
-
-		// Send the missing events
-		missingEvents := make(map[string][]*hashgraph.Event, len(node.Hashgraph))
-		for addr := range numEventsToSend {
-			if numEventsToSend[addr] > 0 { /* it is possible for this to be negative, but that is ok, it just means the peer knows stuff I do not, which I will eventually learn via gossip */
-				totalNumEvents := knownEventNums[addr]
-				for _, event := range node.Hashgraph[addr][totalNumEvents-numEventsToSend[addr]:] {
-					missingEvents[addr] = append(missingEvents[addr], event)
-				}
-			}
-		}
-
-		// Wrap the missing events in a struct for rpc, attach my own address here
-		syncEventsDTO := hashgraph.SyncEventsDTO{
-			SenderAddress: node.Address,
-			MissingEvents: missingEvents,
-		}
-
-		if evaluationMode && c%printPerMrpcCall == 0 {
+		if dl.config.EvaluationMode && c%dl.config.EvaluationInterval == 0 {
 			evalString := createEvaluationString(node, c, startOfGossip)
 			fmt.Println(evalString)
 		}
 
 		node.RWMutex.RUnlock()
 
-		//_ = peerRPCconn.Call("Node.SyncAllEvents", syncEventsDTO, nil) /* V1 */
-		//_ = peerRPCconn.Close()                                        /* V1 */
-		err = randomPeerConnection.Call("Node.SyncAllEvents", syncEventsDTO, nil) /* V2 */
-		handleError(err)
-
 		c++
-		time.Sleep(gossipWaitTime)
 	}
 }
 
-// Read the node addresses and names, return a map from addresses to names
-func readPeerAddresses(path string, localIPAddr string) map[string]string {
+// Gossips with the peer at the address that it is at now, and records the result in the health of the peer. The peer
+// may have moved since I connected to it.
+func (dl *DLedger) gossipOnce(ctx context.Context, peerID hashgraph.MemberID) error {
+	addr, _ := dl.AddressBook.Address(peerID)
+	conn, err := dl.peers.connection(peerID, addr, func(address string) (*grpc.ClientConn, error) {
+		return dialRPC(dl.transport, address, dl.authenticator.clientCredentials(peerID))
+	})
+	if err == nil {
+		err = dl.gossipWith(ctx, wire.NewGossipClient(conn))
+	}
+	if err != nil {
+		if dl.peers.failed(peerID, err, time.Now()) {
+			dl.logger.Warn("Gossip failed, skipping the peer for a while", "peer", dl.peers.name(peerID), "error", err)
+		} else {
+			dl.logger.Debug("Gossip failed again", "peer", dl.peers.name(peerID), "error", err)
+		}
+		return err
+	}
+	if dl.peers.succeeded(peerID, time.Now()) {
+		dl.logger.Info("Gossip recovered", "peer", dl.peers.name(peerID))
+	} else {
+		dl.logger.Debug("Gossiped", "peer", dl.peers.name(peerID), "address", addr)
+	}
+	return nil
+}
+
+// Waits for the given duration, returns false if the context is cancelled before
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// Gossips with a peer: pulls the latest events that the peer knows and pushes the events that it does not know. If I
+// lag behind the events that my peers keep, downloads a snapshot from the peer first and continues from there.
+func (dl *DLedger) gossipWith(ctx context.Context, peer wire.GossipClient) error {
+	ctx, cancel := context.WithTimeout(ctx, gossipCallTimeout)
+	defer cancel()
+
+	node := dl.Node
+
+	if node.NeedsSnapshot() {
+		snapshot, err := pullSnapshot(ctx, peer)
+		if err == nil {
+			err = node.InstallSnapshot(snapshot)
+		}
+		if err != nil {
+			dl.logger.Warn("Could not install snapshot", "error", err)
+			dl.stopOnStoreFailure(err)
+		}
+	}
+	if err := node.StoreError(); err != nil {
+		return err // my events are not persisted, my peers must not learn them
+	}
+
+	// Ask the peer for the latest events it knows, it knows their ancestors too
+	latestEvents, err := pullLatestEvents(ctx, peer)
+	if err != nil {
+		return err
+	}
+
+	// Send the events that the peer does not know but I know, attach my own ID here. My signatures over my latest
+	// states let the peer certify its snapshots for the members that lag behind.
+	syncEventsDTO := hashgraph.SyncEventsDTO{
+		SenderID:          node.ID,
+		MissingEvents:     node.MissingEvents(latestEvents),
+		StateSignatures:   node.StateSignatures(),
+		LastRoundReceived: node.LastRoundReceived(),
+	}
+	_, err = pushMissingEvents(ctx, peer, syncEventsDTO)
+	if errors.Is(err, hashgraph.ErrSenderLagsBehind) {
+		node.RequestSnapshot() // the peer is healthy, I install a snapshot before my next gossip
+		return nil
+	}
+	return err
+}
+
+//ReadPeers : Reads the peers file, returns a map from member IDs to peers.
+// Each line of the file is formatted as "ADDRESS NAME PUBLIC_KEY [STAKE [BALANCE [CERT_FINGERPRINT]]]" where the public
+// key and the fingerprint of the TLS certificate are hex encoded.
+// Members without a stake get the default stake, so that every member has an equal weight if no stakes are given.
+// Members without a balance get the default balance in the genesis of the ledger. Lines starting with # are comments.
+// Addresses whose host is localhost are rewritten to localIPAddr, so that members on one device reach each other at the
+// address they advertise.
+func ReadPeers(path string, localIPAddr string) map[hashgraph.MemberID]Peer {
 	file, err := os.Open(path)
 	handleError(err)
 	defer func() {
 		handleError(file.Close())
 	}()
 
-	// Addr to name map
-	peers := make(map[string]string)
+	// ID to peer map
+	peers := make(map[hashgraph.MemberID]Peer)
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		addrName := strings.Split(scanner.Text(), " ")
-		peers[strings.Replace(addrName[0], "localhost", localIPAddr, 1)] = addrName[1]
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		if len(fields) < 3 {
+			panic("Malformed line in peers file: " + scanner.Text())
+		}
+		publicKey, err := hex.DecodeString(fields[2])
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			panic("Malformed public key of " + fields[1] + " in peers file")
+		}
+		stake := uint64(defaultStake)
+		if len(fields) > 3 {
+			stake, err = strconv.ParseUint(fields[3], 10, 64)
+			if err != nil || stake == 0 {
+				panic("Malformed stake of " + fields[1] + " in peers file")
+			}
+		}
+		balance := uint64(defaultBalance)
+		if len(fields) > 4 {
+			balance, err = strconv.ParseUint(fields[4], 10, 64)
+			if err != nil {
+				panic("Malformed balance of " + fields[1] + " in peers file")
+			}
+		}
+		certFingerprint := ""
+		if len(fields) > 5 {
+			fingerprint, err := hex.DecodeString(fields[5])
+			if err != nil || len(fingerprint) != sha256.Size {
+				panic("Malformed certificate fingerprint of " + fields[1] + " in peers file")
+			}
+			certFingerprint = hex.EncodeToString(fingerprint) // lowercase, like the fingerprints of the certificates
+		}
+		peers[hashgraph.NewMemberID(publicKey)] = Peer{
+			Address:         replaceLocalhost(fields[0], localIPAddr),
+			Name:            fields[1],
+			PublicKey:       publicKey,
+			Stake:           stake,
+			Balance:         balance,
+			CertFingerprint: certFingerprint,
+		}
 	}
 	return peers
 }
 
-//WaitForPeers : Waits for all members in the member list to be online and responsive.
-func (dl *DLedger) WaitForPeers() {
-	peerAvailable := make([]bool, len(dl.PeerAddresses))
-	remainingPeers := len(dl.PeerAddresses)
-	for remainingPeers > 0 {
-		for index, hasAlreadyResponded := range peerAvailable {
+// Returns the address with localIPAddr as its host if its host is localhost, the address as is otherwise
+func replaceLocalhost(address string, localIPAddr string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || host != "localhost" {
+		return address
+	}
+	return net.JoinHostPort(localIPAddr, port)
+}
+
+//ReadPrivateKey : Reads a hex encoded ed25519 seed from the given file and returns the corresponding private key
+func ReadPrivateKey(path string) ed25519.PrivateKey {
+	content, err := ioutil.ReadFile(path)
+	handleError(err)
+	seed, err := hex.DecodeString(strings.TrimSpace(string(content)))
+	handleError(err)
+	if len(seed) != ed25519.SeedSize {
+		panic("Key file " + path + " does not contain an ed25519 seed")
+	}
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+//WaitForPeers : Waits until the peers that are online and responsive hold, along with me, more than 2/3 of the stake,
+// which is enough to reach consensus. The other peers are gossiped to when they come online. Returns the error of the
+// context if it is cancelled before.
+func (dl *DLedger) WaitForPeers(ctx context.Context) error {
+	available := make(map[hashgraph.MemberID]bool)
+	stake := dl.Node.Stakes[dl.MyID]
+	for !dl.isSuperMajority(stake) {
+		for _, peerID := range dl.PeerIDs {
 			// we have already reached this peer
-			if hasAlreadyResponded {
+			if available[peerID] {
 				continue
 			}
 
-			rpcConnection, err := rpc.Dial("tcp", dl.PeerAddresses[index])
-			if err != nil {
-				time.Sleep(connectionAttemptDelayTime)
-				continue
-			} else {
-				_ = rpcConnection.Close()
-				peerAvailable[index] = true
-				remainingPeers--
+			addr, _ := dl.AddressBook.Address(peerID)
+			if conn, err := dl.transport.Dial(addr); err == nil {
+				_ = conn.Close()
+				available[peerID] = true
+				stake += dl.Node.Stakes[peerID]
 			}
 		}
+		if !dl.isSuperMajority(stake) && !sleep(ctx, connectionAttemptDelayTime) {
+			return ctx.Err()
+		}
 	}
+	return nil
 }
 
-// Serves RPC calls in a go routine
-func listenForRPCConnections(listener *net.TCPListener) {
-	for {
-		conn, err := listener.Accept()
-		if err != nil {
-			continue
-		}
-		go rpc.ServeConn(conn)
+// Returns true if the stake is more than 2/3 of the stake of all members
+func (dl *DLedger) isSuperMajority(stake uint64) bool {
+	totalStake := uint64(0)
+	for _, memberStake := range dl.Node.Stakes {
+		totalStake += memberStake
+	}
+	return 3*stake > 2*totalStake
+}
+
+// Returns the address that my peers reach me at when none is advertised. A member that listens on a host, e.g. on
+// 127.0.0.1 or localhost in a cluster on one device, is reached at that host. A member that listens on all interfaces
+// is reached at the IP address of one of them.
+func defaultAdvertisedAddress(listenAddress string) (string, error) {
+	host, port, err := net.SplitHostPort(listenAddress)
+	if err != nil {
+		return "", err
+	}
+	if ip := net.ParseIP(host); host != "" && (ip == nil || !ip.IsUnspecified()) {
+		return listenAddress, nil
 	}
+	return net.JoinHostPort(getLocalAddress(), port), nil
 }
 
-// Returns the local address of this device
+// Returns the IP address of this device on its network, found among the addresses of its network interfaces that are
+// up. IPv4 addresses are preferred, and the loopback address is returned if the device is not on a network, so that
+// the members on an air-gapped device can still reach each other.
 func getLocalAddress() string {
-	conn, err := net.Dial("udp", "eng.ku.edu.tr:80")
-	handleError(err)
-	defer func() {
-		handleError(conn.Close())
-	}()
-	localAddr := conn.LocalAddr().(*net.UDPAddr)
-	return localAddr.IP.String()
+	interfaces, err := net.Interfaces()
+	if err != nil {
+		return loopbackAddress
+	}
+	ipv6Address := ""
+	for _, i := range interfaces {
+		if i.Flags&net.FlagUp == 0 || i.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addresses, err := i.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, address := range addresses {
+			ipNet, ok := address.(*net.IPNet)
+			if !ok || !ipNet.IP.IsGlobalUnicast() { // link-local addresses are not reachable from other links
+				continue
+			}
+			if ipNet.IP.To4() != nil {
+				return ipNet.IP.String()
+			}
+			if ipv6Address == "" {
+				ipv6Address = ipNet.IP.String()
+			}
+		}
+	}
+	if ipv6Address != "" {
+		return ipv6Address
+	}
+	return loopbackAddress
 }
 
 // Auxiliary for any error
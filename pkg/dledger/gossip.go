@@ -0,0 +1,119 @@
+package dledger
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"../hashgraph"
+	"../wire"
+)
+
+// gRPC service that my peers gossip to, it passes the calls to my node
+type gossipService struct {
+	wire.UnimplementedGossipServer
+	node           *hashgraph.Node
+	authenticator  *memberAuthenticator // authenticates the callers, nil if the calls are not authenticated
+	onStoreFailure func(err error)      // stops me when my store fails
+}
+
+// Replies with the latest events that I know of each member
+func (s *gossipService) GetLatestEvents(_ context.Context, request *wire.GetLatestEventsRequest) (*wire.GetLatestEventsResponse, error) {
+	if err := wire.CheckVersion(request.GetVersion()); err != nil {
+		return nil, err
+	}
+	var latestEvents hashgraph.LatestEventsDTO
+	if err := s.node.GetLatestEvents(true, &latestEvents); err != nil {
+		return nil, err
+	}
+	return &wire.GetLatestEventsResponse{Version: wire.ProtocolVersion, Tips: wire.FromTips(latestEvents.Tips)}, nil
+}
+
+// Inserts the events that the peer sent, replies with false if I need a snapshot to insert them, or tells the peer that
+// it needs a snapshot if I pruned the parents of its events. An authenticated peer can only send events in its own
+// name, since my next event takes its latest event as its other-parent.
+func (s *gossipService) SyncEvents(ctx context.Context, request *wire.SyncEventsRequest) (*wire.SyncEventsResponse, error) {
+	if err := wire.CheckVersion(request.GetVersion()); err != nil {
+		return nil, err
+	}
+	if caller, ok := s.authenticator.caller(ctx); ok && string(caller) != request.GetSenderId() {
+		return nil, status.Errorf(codes.PermissionDenied, "%s can not send events as %s", caller, request.GetSenderId())
+	}
+	syncEventsDTO := hashgraph.SyncEventsDTO{
+		SenderID:          hashgraph.MemberID(request.GetSenderId()),
+		MissingEvents:     wire.ToEvents(request.GetMissingEvents()),
+		StateSignatures:   wire.ToStateSignatures(request.GetStateSignatures()),
+		LastRoundReceived: request.GetLastRoundReceived(),
+	}
+	var success bool
+	if err := s.node.SyncAllEvents(syncEventsDTO, &success); errors.Is(err, hashgraph.ErrSenderLagsBehind) {
+		return &wire.SyncEventsResponse{Version: wire.ProtocolVersion, SenderNeedsSnapshot: true}, nil
+	} else if errors.Is(err, hashgraph.ErrStoreFailed) {
+		s.onStoreFailure(err)
+		return nil, status.Error(codes.Internal, err.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &wire.SyncEventsResponse{Version: wire.ProtocolVersion, Success: success}, nil
+}
+
+// Replies with the latest snapshot whose state members with more than 2/3 of the stake signed, NotFound until one is
+// certified
+func (s *gossipService) GetLatestSnapshot(_ context.Context, request *wire.GetLatestSnapshotRequest) (*wire.GetLatestSnapshotResponse, error) {
+	if err := wire.CheckVersion(request.GetVersion()); err != nil {
+		return nil, err
+	}
+	var snapshot hashgraph.Snapshot
+	if err := s.node.GetLatestSnapshot(true, &snapshot); errors.Is(err, hashgraph.ErrNoSnapshot) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	} else if err != nil {
+		return nil, err
+	}
+	return &wire.GetLatestSnapshotResponse{Version: wire.ProtocolVersion, Snapshot: wire.FromSnapshot(&snapshot)}, nil
+}
+
+// Pulls the latest events that the peer knows of each member
+func pullLatestEvents(ctx context.Context, peer wire.GossipClient) (hashgraph.LatestEventsDTO, error) {
+	response, err := peer.GetLatestEvents(ctx, &wire.GetLatestEventsRequest{Version: wire.ProtocolVersion})
+	if err != nil {
+		return hashgraph.LatestEventsDTO{}, err
+	}
+	return hashgraph.LatestEventsDTO{Tips: wire.ToTips(response.GetTips())}, wire.CheckVersion(response.GetVersion())
+}
+
+// Pushes the events that the peer does not know along with my signatures over my latest states, returns false if the
+// peer needs a snapshot to insert them, and ErrSenderLagsBehind if I need one
+func pushMissingEvents(ctx context.Context, peer wire.GossipClient, events hashgraph.SyncEventsDTO) (bool, error) {
+	request := &wire.SyncEventsRequest{
+		Version:           wire.ProtocolVersion,
+		SenderId:          string(events.SenderID),
+		MissingEvents:     wire.FromEvents(events.MissingEvents),
+		StateSignatures:   wire.FromStateSignatures(events.StateSignatures),
+		LastRoundReceived: events.LastRoundReceived,
+	}
+	response, err := peer.SyncEvents(ctx, request)
+	if err != nil {
+		return false, err
+	}
+	if err := wire.CheckVersion(response.GetVersion()); err != nil {
+		return false, err
+	}
+	if response.GetSenderNeedsSnapshot() {
+		return false, hashgraph.ErrSenderLagsBehind
+	}
+	return response.GetSuccess(), nil
+}
+
+// Pulls the latest certified snapshot of the peer
+func pullSnapshot(ctx context.Context, peer wire.GossipClient) (*hashgraph.Snapshot, error) {
+	response, err := peer.GetLatestSnapshot(ctx, &wire.GetLatestSnapshotRequest{Version: wire.ProtocolVersion})
+	if err != nil {
+		return nil, err
+	}
+	if err := wire.CheckVersion(response.GetVersion()); err != nil {
+		return nil, err
+	}
+	return wire.ToSnapshot(response.GetSnapshot()), nil
+}
@@ -0,0 +1,159 @@
+package dledger
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"../hashgraph"
+)
+
+const (
+	minRetryDelay = 200 * time.Millisecond // a peer is not chosen for gossip for this long after a failed gossip
+	maxRetryDelay = 10 * time.Second       // the delay doubles with every consecutive failure up to this long
+)
+
+//PeerHealth : How reachable a peer is for my gossip
+type PeerHealth struct {
+	ID                  hashgraph.MemberID // ID of the peer
+	Name                string             // human readable name of the peer
+	Address             string             // address that I last connected to, empty if I did not connect yet
+	Reachable           bool               // false after a failed gossip until the next successful gossip
+	ConsecutiveFailures int                // number of failed gossips since the last successful gossip
+	LastSuccess         time.Time          // time of the last successful gossip, zero if there is none
+	LastError           string             // error of the last failed gossip, empty if there is none
+	RetryAt             time.Time          // the peer is not chosen for gossip before this time
+}
+
+// Connection to a peer and the results of my gossips with it
+type peerState struct {
+	PeerHealth
+	conn *grpc.ClientConn // nil until I connect to the peer, and after a failed gossip
+}
+
+// Peers that I gossip with, shared by the gossip routine and the health queries
+type peerSet struct {
+	sync.Mutex
+	ids   []hashgraph.MemberID              // IDs of the peers, for random access
+	peers map[hashgraph.MemberID]*peerState // map of peer ID -> state of the peer
+}
+
+// Creates the states of the peers, every peer is assumed to be reachable until a gossip with it fails
+func newPeerSet(peerIDs []hashgraph.MemberID, names map[hashgraph.MemberID]string) *peerSet {
+	s := &peerSet{
+		ids:   peerIDs,
+		peers: make(map[hashgraph.MemberID]*peerState, len(peerIDs)),
+	}
+	for _, id := range peerIDs {
+		s.peers[id] = &peerState{PeerHealth: PeerHealth{ID: id, Name: names[id], Reachable: true}}
+	}
+	return s
+}
+
+// Chooses a random peer among the peers that are not waiting for a retry, ok is false if every peer is waiting
+func (s *peerSet) choose(now time.Time) (id hashgraph.MemberID, ok bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	available := make([]hashgraph.MemberID, 0, len(s.ids))
+	for _, id := range s.ids {
+		if !now.Before(s.peers[id].RetryAt) {
+			available = append(available, id)
+		}
+	}
+	if len(available) == 0 {
+		return "", false
+	}
+	return available[rand.Intn(len(available))], true
+}
+
+// Returns the connection to the peer at the given address, connects again if the peer moved to the address since I
+// connected to it
+func (s *peerSet) connection(id hashgraph.MemberID, address string, dial func(address string) (*grpc.ClientConn, error)) (*grpc.ClientConn, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	peer := s.peers[id]
+	if peer.conn != nil && peer.Address == address {
+		return peer.conn, nil
+	}
+	conn, err := dial(address)
+	if err != nil {
+		return nil, err
+	}
+	if peer.conn != nil {
+		_ = peer.conn.Close()
+	}
+	peer.conn = conn
+	peer.Address = address
+	return conn, nil
+}
+
+// Records a successful gossip with the peer, returns true if the peer was unreachable before
+func (s *peerSet) succeeded(id hashgraph.MemberID, now time.Time) (recovered bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	peer := s.peers[id]
+	recovered = !peer.Reachable
+	peer.Reachable = true
+	peer.ConsecutiveFailures = 0
+	peer.LastSuccess = now
+	peer.RetryAt = time.Time{}
+	return recovered
+}
+
+// Records a failed gossip with the peer and backs off from it exponentially, returns true if the peer was reachable
+// before. The connection is closed, so that the next gossip with the peer connects again.
+func (s *peerSet) failed(id hashgraph.MemberID, err error, now time.Time) (lost bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	peer := s.peers[id]
+	lost = peer.Reachable
+	peer.Reachable = false
+	peer.ConsecutiveFailures++
+	peer.LastError = err.Error()
+	delay := minRetryDelay
+	for i := 1; i < peer.ConsecutiveFailures && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	peer.RetryAt = now.Add(min(delay, maxRetryDelay))
+	if peer.conn != nil {
+		_ = peer.conn.Close()
+		peer.conn = nil
+	}
+	return lost
+}
+
+// Returns the name of the peer, names do not change so the set is not locked
+func (s *peerSet) name(id hashgraph.MemberID) string {
+	return s.peers[id].Name
+}
+
+// Returns a copy of the health of every peer, in the order of the peer IDs
+func (s *peerSet) health() []PeerHealth {
+	s.Lock()
+	defer s.Unlock()
+
+	health := make([]PeerHealth, len(s.ids))
+	for i, id := range s.ids {
+		health[i] = s.peers[id].PeerHealth
+	}
+	return health
+}
+
+// Closes the connections to all peers
+func (s *peerSet) close() {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, peer := range s.peers {
+		if peer.conn != nil {
+			_ = peer.conn.Close()
+			peer.conn = nil
+		}
+	}
+}
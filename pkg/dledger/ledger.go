@@ -0,0 +1,233 @@
+package dledger
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	"../hashgraph"
+)
+
+//Transfer : A statement of money transfer from a sender to a receiver, which is the transaction of the ledger. Transfers
+// are signed by their sender, so that any member can relay the transfer of a client without being able to forge one.
+// Transfers of a sender are numbered by their nonces, so that a signed transfer can not be applied more than once.
+type Transfer struct {
+	SenderID   hashgraph.MemberID `json:"sender_id"`           // member ID of sender
+	ReceiverID hashgraph.MemberID `json:"receiver_id"`         // member ID of receiver
+	Amount     uint64             `json:"amount"`              // amount, an integer so that every member computes the same balances
+	Nonce      uint64             `json:"nonce"`               // 1 for the first transfer of the sender, one more than the previous one for the others
+	Signature  string             `json:"signature,omitempty"` // hex encoded signature of the sender over the rest of the transfer
+}
+
+//Sign : Signs the transfer with the private key of the sender, the signature covers every field including the nonce
+func (t *Transfer) Sign(privateKey ed25519.PrivateKey) {
+	t.Signature = hex.EncodeToString(ed25519.Sign(privateKey, t.signedBytes()))
+}
+
+//VerifySignature : Returns true if the transfer is signed by its sender
+func (t Transfer) VerifySignature() bool {
+	publicKey := t.SenderID.PublicKey()
+	signature, err := hex.DecodeString(t.Signature)
+	if publicKey == nil || err != nil {
+		return false
+	}
+	return ed25519.Verify(publicKey, t.signedBytes(), signature)
+}
+
+// Canonical encoding of the transfer without its signature
+func (t Transfer) signedBytes() []byte {
+	t.Signature = ""
+	encoded, err := json.Marshal(t)
+	handleError(err)
+	return encoded
+}
+
+//Encode : Encodes the transfer as a transaction of the hashgraph
+func (t Transfer) Encode() hashgraph.Transaction {
+	encoded, err := json.Marshal(t)
+	handleError(err)
+	return encoded
+}
+
+//DecodeTransfer : Decodes a transaction of the hashgraph as a transfer
+func DecodeTransfer(tx hashgraph.Transaction) (Transfer, error) {
+	var transfer Transfer
+	err := json.Unmarshal(tx, &transfer)
+	return transfer, err
+}
+
+//Ledger : The money transfer application on top of the hashgraph. Transfers are applied in consensus order starting from
+// the genesis balances, so every member arrives at the same balances. A transfer that would overdraw its sender is
+// rejected, and since every member applies the same transfers in the same order, every member rejects the same ones.
+type Ledger struct {
+	sync.RWMutex
+	balances          map[hashgraph.MemberID]uint64 // balances after the delivered transfers
+	committedBalances map[hashgraph.MemberID]uint64 // balances after the last committed round
+	nonces            map[hashgraph.MemberID]uint64 // nonces of the last delivered transfers of the senders
+	committedNonces   map[hashgraph.MemberID]uint64 // nonces of the last transfers of the senders in the committed rounds
+	appliedCount      int                           // number of transfers that are applied
+	rejectedCount     int                           // number of transfers that are rejected
+	lastCommitted     uint32                        // last round that is committed
+}
+
+// State of the ledger as it is included in snapshots
+type ledgerState struct {
+	Balances      map[hashgraph.MemberID]uint64 `json:"balances"`
+	Nonces        map[hashgraph.MemberID]uint64 `json:"nonces"`
+	AppliedCount  int                           `json:"applied_count"`
+	RejectedCount int                           `json:"rejected_count"`
+	LastCommitted uint32                        `json:"last_committed"`
+}
+
+//NewLedger : Creates a ledger with the given genesis balances. Only the members in the genesis can receive transfers.
+func NewLedger(genesis map[hashgraph.MemberID]uint64) *Ledger {
+	l := &Ledger{
+		balances:          make(map[hashgraph.MemberID]uint64, len(genesis)),
+		committedBalances: make(map[hashgraph.MemberID]uint64, len(genesis)),
+		nonces:            make(map[hashgraph.MemberID]uint64, len(genesis)),
+		committedNonces:   make(map[hashgraph.MemberID]uint64, len(genesis)),
+	}
+	for id, balance := range genesis {
+		l.balances[id] = balance
+		l.committedBalances[id] = balance
+	}
+	return l
+}
+
+//DeliverTx : Applies a transaction that reached consensus. Transactions that are not valid transfers, transfers that
+// are not signed by their sender, transfers whose nonce does not follow the previous transfer of their sender and
+// transfers that overdraw their sender are rejected.
+func (l *Ledger) DeliverTx(tx hashgraph.Transaction, e *hashgraph.Event) {
+	l.apply(tx)
+}
+
+// Applies the transaction to the balances, returns false if the transaction is rejected. A signed transfer uses up its
+// nonce even if it overdraws its sender, so the sender can continue with the next nonce.
+func (l *Ledger) apply(tx hashgraph.Transaction) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	transfer, err := DecodeTransfer(tx)
+	if err != nil || transfer.Amount == 0 || !transfer.VerifySignature() {
+		l.rejectedCount++
+		return false
+	}
+	senderBalance, okSender := l.balances[transfer.SenderID]
+	_, okReceiver := l.balances[transfer.ReceiverID]
+	if !okSender || transfer.Nonce != l.nonces[transfer.SenderID]+1 {
+		l.rejectedCount++ // a replayed or an out of order transfer
+		return false
+	}
+	l.nonces[transfer.SenderID] = transfer.Nonce
+	if !okReceiver || senderBalance < transfer.Amount {
+		l.rejectedCount++
+		return false
+	}
+	l.balances[transfer.SenderID] -= transfer.Amount
+	l.balances[transfer.ReceiverID] += transfer.Amount
+	l.appliedCount++
+	return true
+}
+
+//Commit : Makes the balances after the transfers delivered in the round visible to the queries
+func (l *Ledger) Commit(round uint32) {
+	l.Lock()
+	defer l.Unlock()
+
+	for id, balance := range l.balances {
+		l.committedBalances[id] = balance
+	}
+	for id, nonce := range l.nonces {
+		l.committedNonces[id] = nonce
+	}
+	l.lastCommitted = round
+}
+
+//SnapshotState : Returns the committed state of the ledger
+func (l *Ledger) SnapshotState() []byte {
+	l.RLock()
+	defer l.RUnlock()
+
+	state, err := json.Marshal(ledgerState{
+		Balances:      l.committedBalances,
+		Nonces:        l.committedNonces,
+		AppliedCount:  l.appliedCount,
+		RejectedCount: l.rejectedCount,
+		LastCommitted: l.lastCommitted,
+	})
+	handleError(err)
+	return state
+}
+
+//RestoreState : Replaces the state of the ledger with the state in a snapshot
+func (l *Ledger) RestoreState(state []byte) error {
+	var restored ledgerState
+	if err := json.Unmarshal(state, &restored); err != nil {
+		return err
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	l.balances = make(map[hashgraph.MemberID]uint64, len(restored.Balances))
+	l.committedBalances = make(map[hashgraph.MemberID]uint64, len(restored.Balances))
+	for id, balance := range restored.Balances {
+		l.balances[id] = balance
+		l.committedBalances[id] = balance
+	}
+	l.nonces = make(map[hashgraph.MemberID]uint64, len(restored.Nonces))
+	l.committedNonces = make(map[hashgraph.MemberID]uint64, len(restored.Nonces))
+	for id, nonce := range restored.Nonces {
+		l.nonces[id] = nonce
+		l.committedNonces[id] = nonce
+	}
+	l.appliedCount = restored.AppliedCount
+	l.rejectedCount = restored.RejectedCount
+	l.lastCommitted = restored.LastCommitted
+	return nil
+}
+
+//Balance : Returns the committed balance of the member, ok is false if the member is not in the ledger
+func (l *Ledger) Balance(id hashgraph.MemberID) (balance uint64, ok bool) {
+	l.RLock()
+	defer l.RUnlock()
+
+	balance, ok = l.committedBalances[id]
+	return balance, ok
+}
+
+//Nonce : Returns the nonce of the last committed transfer of the member, 0 if the member did not send any transfer yet
+func (l *Ledger) Nonce(id hashgraph.MemberID) uint64 {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.committedNonces[id]
+}
+
+//Balances : Returns the committed balances of all members
+func (l *Ledger) Balances() map[hashgraph.MemberID]uint64 {
+	l.RLock()
+	defer l.RUnlock()
+
+	balances := make(map[hashgraph.MemberID]uint64, len(l.committedBalances))
+	for id, balance := range l.committedBalances {
+		balances[id] = balance
+	}
+	return balances
+}
+
+//TransferCounts : Returns the number of applied and rejected transfers
+func (l *Ledger) TransferCounts() (applied int, rejected int) {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.appliedCount, l.rejectedCount
+}
+
+//LastCommittedRound : Returns the last round whose transfers are applied to the ledger
+func (l *Ledger) LastCommittedRound() uint32 {
+	l.RLock()
+	defer l.RUnlock()
+
+	return l.lastCommitted
+}
@@ -0,0 +1,90 @@
+package dledger
+
+import (
+	"math/rand"
+	"time"
+
+	"../hashgraph"
+)
+
+const (
+	UniformAmounts     = "uniform"     // Amounts are uniformly distributed between the minimum and the maximum amount
+	ExponentialAmounts = "exponential" // Amounts are the minimum amount plus an exponentially distributed extra, up to the maximum amount
+
+	defaultLoadRate      = 20  // transfers per second, which is about 2 transfers per event of a member with the default gossip wait time
+	defaultLoadAmountMin = 10  // minimum amount of a generated transfer
+	defaultLoadAmountMax = 500 // maximum amount of a generated transfer
+)
+
+//LoadGenerator : Submits random transfers from a member to other members at a steady rate, which keeps the ledger busy
+// for evaluation. The transfers are submitted to the buffer of the member like the transfers of its clients, so the
+// hashgraph does not know that they are generated. Configure the fields before calling Start.
+type LoadGenerator struct {
+	Rate         float64              // transfers per second
+	Distribution string               // distribution of the amounts, UniformAmounts or ExponentialAmounts
+	AmountMin    uint64               // minimum amount of a transfer
+	AmountMax    uint64               // maximum amount of a transfer
+	Targets      []hashgraph.MemberID // members that the transfers are sent to, each transfer chooses one randomly
+	dl           *DLedger
+	stop         chan struct{}
+}
+
+//NewLoadGenerator : Creates a load generator for the member with the default rate and amounts, which sends uniformly
+// distributed amounts to all other members
+func NewLoadGenerator(dl *DLedger) *LoadGenerator {
+	return &LoadGenerator{
+		Rate:         defaultLoadRate,
+		Distribution: UniformAmounts,
+		AmountMin:    defaultLoadAmountMin,
+		AmountMax:    defaultLoadAmountMax,
+		Targets:      dl.PeerIDs,
+		dl:           dl,
+	}
+}
+
+//Start : Starts submitting transfers in a go routine until Stop is called. Does nothing if the rate is not positive or
+// there are no targets.
+func (g *LoadGenerator) Start() {
+	if g.Rate <= 0 || len(g.Targets) == 0 || g.stop != nil {
+		return
+	}
+	g.stop = make(chan struct{})
+	go g.run(g.stop)
+}
+
+//Stop : Stops submitting transfers
+func (g *LoadGenerator) Stop() {
+	if g.stop != nil {
+		close(g.stop)
+		g.stop = nil
+	}
+}
+
+// Submits a transfer at every tick until stop is closed
+func (g *LoadGenerator) run(stop chan struct{}) {
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / g.Rate))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		target := g.Targets[rand.Intn(len(g.Targets))]
+		_, _ = g.dl.PerformTransaction(target, g.randomAmount()) // transfers that my balance is not enough for are skipped
+	}
+}
+
+// Returns a random amount from the distribution of the generator, which is at least 1
+func (g *LoadGenerator) randomAmount() uint64 {
+	amountMin := max(g.AmountMin, 1)
+	amountMax := max(g.AmountMax, amountMin)
+	switch g.Distribution {
+	case ExponentialAmounts:
+		// The mean of the extra is half of the range, larger amounts are cut at the maximum
+		extra := rand.ExpFloat64() * float64(amountMax-amountMin) / 2
+		return min(amountMin+uint64(extra), amountMax)
+	default:
+		return amountMin + uint64(rand.Int63n(int64(amountMax-amountMin)+1))
+	}
+}
@@ -0,0 +1,186 @@
+package dledger
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"fmt"
+	"testing"
+	"time"
+
+	"../hashgraph"
+)
+
+// Members that gossip over a memory network, along with the peers and keys that they are started from
+type testCluster struct {
+	network      *MemoryNetwork
+	peers        map[hashgraph.MemberID]Peer
+	ids          []hashgraph.MemberID
+	addresses    []string
+	certificates []tls.Certificate // self-signed certificates of the members, pinned in the peers, nil for plaintext
+	members      []*DLedger
+}
+
+// Starts members that gossip over a memory network, with plaintext connections and without persistence
+func startMembers(t *testing.T, count int, balance uint64) []*DLedger {
+	return startCluster(t, count, balance, false).members
+}
+
+// Starts members that gossip over a memory network without persistence. With secure, every member has a self-signed
+// certificate that is pinned in the peers and the members authenticate each other with mutual TLS.
+func startCluster(t *testing.T, count int, balance uint64, secure bool) *testCluster {
+	cluster := &testCluster{network: NewMemoryNetwork(), peers: make(map[hashgraph.MemberID]Peer)}
+	keys := make([]ed25519.PrivateKey, count)
+	for i := range keys {
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys[i] = privateKey
+		peer := Peer{
+			Address:   fmt.Sprintf("member%d", i),
+			Name:      fmt.Sprintf("Member %d", i),
+			PublicKey: publicKey,
+			Stake:     1,
+			Balance:   balance,
+		}
+		if secure {
+			certificate := selfSignedCertificate(t, peer.Name)
+			peer.CertFingerprint = CertificateFingerprint(certificate.Certificate[0])
+			cluster.certificates = append(cluster.certificates, certificate)
+		}
+		id := hashgraph.NewMemberID(publicKey)
+		cluster.peers[id] = peer
+		cluster.ids = append(cluster.ids, id)
+		cluster.addresses = append(cluster.addresses, peer.Address)
+	}
+
+	for i, key := range keys {
+		config := DefaultConfig()
+		config.ListenAddress = cluster.addresses[i]
+		config.DataDir = ""
+		config.GossipInterval = 10 * time.Millisecond
+		config.LogLevel = "error"
+		config.EvaluationMode = false
+		var tlsConfig *TLSConfig
+		if secure {
+			tlsConfig = &TLSConfig{Certificate: cluster.certificates[i]}
+		}
+		cluster.members = append(cluster.members, NewDLedgerFromPeers(config, cluster.peers, key, cluster.network, tlsConfig))
+	}
+	for _, member := range cluster.members {
+		member.Start(context.Background())
+	}
+	return cluster
+}
+
+func TestMembersAgreeOverMemoryNetwork(t *testing.T) {
+	const memberCount = 4
+	const genesisBalance = 1000
+	members := startMembers(t, memberCount, genesisBalance)
+
+	// Every member sends a transfer to the next member
+	ids := make([]string, memberCount)
+	for i, member := range members {
+		id, err := member.PerformTransaction(members[(i+1)%memberCount].MyID, uint64(10*(i+1)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[i] = id
+	}
+
+	// Wait until every member commits the rounds that the transfers are received in
+	deadline := time.Now().Add(30 * time.Second)
+	lastRound := uint32(0)
+	for i, member := range members {
+		for {
+			status := member.TransactionStatus(ids[i])
+			if status.State == TxConsensus {
+				if !status.Applied {
+					t.Fatalf("transfer of member %d is rejected", i)
+				}
+				if status.RoundReceived > lastRound {
+					lastRound = status.RoundReceived
+				}
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("transfer of member %d did not reach consensus, it is %s", i, status.State)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	for i, member := range members {
+		for member.Ledger.LastCommittedRound() < lastRound {
+			if time.Now().After(deadline) {
+				t.Fatalf("member %d did not commit round %d", i, lastRound)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	for i, member := range members {
+		if err := member.Stop(); err != nil {
+			t.Errorf("member %d did not stop cleanly: %s", i, err)
+		}
+	}
+
+	// Members agree on the position of every event that reached consensus on them, events before the first one in
+	// ConsensusEvents are pruned
+	positions := make([]map[int]string, len(members))
+	for i, member := range members {
+		positions[i] = make(map[int]string)
+		for j, e := range member.Node.ConsensusEvents {
+			positions[i][member.Node.PrunedConsensusCount+j] = e.Hash()
+		}
+	}
+	for i := 1; i < len(members); i++ {
+		compared := 0
+		for position, hash := range positions[i] {
+			if expected, ok := positions[0][position]; ok {
+				if hash != expected {
+					t.Fatalf("member %d orders event %s at %d, member 0 orders %s there", i, hash, position, expected)
+				}
+				compared++
+			}
+		}
+		if compared == 0 {
+			t.Fatalf("no event reached consensus on both member %d and member 0", i)
+		}
+	}
+
+	// Members agree on the balances, which only the transfers changed
+	for i, member := range members {
+		for j, other := range members {
+			expected := uint64(genesisBalance - 10*(j+1) + 10*((j+memberCount-1)%memberCount+1))
+			if balance, _ := member.Ledger.Balance(other.MyID); balance != expected {
+				t.Fatalf("member %d sees the balance of member %d as %d, expected %d", i, j, balance, expected)
+			}
+		}
+	}
+}
+
+// A member waits only for the peers that hold a supermajority of the stake along with it, so it starts while a peer is
+// down for good
+func TestWaitForPeersNeedsOnlyASupermajority(t *testing.T) {
+	members := startMembers(t, 4, 1000)
+	defer func() {
+		for _, member := range members {
+			_ = member.Stop()
+		}
+	}()
+
+	_ = members[3].Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := members[0].WaitForPeers(ctx); err != nil {
+		t.Fatalf("member did not find 3 of 4 members online: %s", err)
+	}
+
+	_ = members[2].Stop()
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := members[0].WaitForPeers(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("member found a supermajority with 2 of 4 members online, the error is %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package dledger
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"../hashgraph"
+	"../wire"
+)
+
+//TLSConfig : Certificate that a member presents to the members and clients it connects to, and the CAs that sign the
+// certificates of the members. Certificates that are signed by a CA identify their member by their common name, which
+// is the name of the member in the peers file. Certificates of the members that are pinned by their fingerprints in
+// the peers file do not need to be signed by a CA. Clients have no certificate, they only verify the member they call.
+type TLSConfig struct {
+	Certificate tls.Certificate // certificate of this member along with its private key, empty for clients
+	CAs         *x509.CertPool  // CAs that sign the certificates of the members, nil if every certificate is pinned
+}
+
+//LoadTLSConfig : Loads the PEM encoded certificate and key of a member unless certFile is empty, and the PEM encoded
+// certificates of the CAs from caFile unless it is empty. Clients load only the CAs.
+func LoadTLSConfig(certFile string, keyFile string, caFile string) (*TLSConfig, error) {
+	config := &TLSConfig{}
+	if certFile != "" {
+		certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		config.Certificate = certificate
+	}
+	if caFile != "" {
+		caPEM, err := ioutil.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		config.CAs = x509.NewCertPool()
+		if !config.CAs.AppendCertsFromPEM(caPEM) {
+			return nil, fmt.Errorf("no certificates are found in %s", caFile)
+		}
+	}
+	return config, nil
+}
+
+//CertificateFingerprint : Returns the hex encoded SHA-256 hash of a DER encoded certificate, which pins the certificate
+// of a member in the peers file
+func CertificateFingerprint(der []byte) string {
+	hash := sha256.Sum256(der)
+	return hex.EncodeToString(hash[:])
+}
+
+// Authenticates the members by their certificates, both when they call me and when I call them
+type memberAuthenticator struct {
+	config *TLSConfig
+	peers  map[hashgraph.MemberID]Peer
+}
+
+// Returns the authenticator of the members, nil if the connections are not authenticated
+func newMemberAuthenticator(config *TLSConfig, peers map[hashgraph.MemberID]Peer) *memberAuthenticator {
+	if config == nil {
+		return nil
+	}
+	return &memberAuthenticator{config: config, peers: peers}
+}
+
+// Returns the ID of the member that the presented chain of certificates belongs to, pinned certificates are checked
+// before the certificates that are signed by a CA
+func (a *memberAuthenticator) identify(chain []*x509.Certificate) (hashgraph.MemberID, error) {
+	if len(chain) == 0 {
+		return "", errors.New("no certificate is presented")
+	}
+	leaf := chain[0]
+	if now := time.Now(); now.Before(leaf.NotBefore) || now.After(leaf.NotAfter) {
+		return "", fmt.Errorf("certificate of %s is expired or not valid yet", leaf.Subject.CommonName)
+	}
+
+	fingerprint := CertificateFingerprint(leaf.Raw)
+	for id, member := range a.peers {
+		if member.CertFingerprint == fingerprint {
+			return id, nil
+		}
+	}
+
+	if a.config.CAs != nil {
+		intermediates := x509.NewCertPool()
+		for _, certificate := range chain[1:] {
+			intermediates.AddCert(certificate)
+		}
+		options := x509.VerifyOptions{
+			Roots:         a.config.CAs,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny}, // the same certificate is used by servers and clients
+		}
+		if _, err := leaf.Verify(options); err == nil {
+			for id, member := range a.peers {
+				if member.CertFingerprint == "" && member.Name == leaf.Subject.CommonName {
+					return id, nil
+				}
+			}
+		}
+	}
+	return "", fmt.Errorf("certificate %s of %s does not belong to a member", fingerprint, leaf.Subject.CommonName)
+}
+
+// Returns the credentials of my server. Members present their certificates, clients connect without a certificate and
+// are only allowed to call the transaction service. Connections are plaintext if they are not authenticated.
+func (a *memberAuthenticator) serverCredentials() credentials.TransportCredentials {
+	if a == nil {
+		return insecure.NewCredentials()
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{a.config.Certificate},
+		ClientAuth:   tls.RequestClientCert, // certificates are verified by identify
+		MinVersion:   tls.VersionTLS13,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			if len(state.PeerCertificates) == 0 {
+				return nil // a client
+			}
+			_, err := a.identify(state.PeerCertificates)
+			return err
+		},
+	})
+}
+
+// Interceptor of my server that only lets members call the gossip service, the transaction service is open to clients
+func (a *memberAuthenticator) authorizeGossip(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if a != nil && strings.HasPrefix(info.FullMethod, "/"+wire.Gossip_ServiceDesc.ServiceName+"/") {
+		if _, ok := a.caller(ctx); !ok {
+			return nil, status.Error(codes.Unauthenticated, "only members can gossip")
+		}
+	}
+	return handler(ctx, request)
+}
+
+// Returns the credentials of my connections to the member with the given ID, or to any member if the ID is empty.
+// Connections are plaintext if they are not authenticated.
+func (a *memberAuthenticator) clientCredentials(expectedID hashgraph.MemberID) credentials.TransportCredentials {
+	if a == nil {
+		return insecure.NewCredentials()
+	}
+	var certificates []tls.Certificate
+	if len(a.config.Certificate.Certificate) > 0 {
+		certificates = append(certificates, a.config.Certificate) // clients do not present a certificate
+	}
+	return credentials.NewTLS(&tls.Config{
+		Certificates:       certificates,
+		InsecureSkipVerify: true, // members are verified by identify instead of their host names, which may change
+		MinVersion:         tls.VersionTLS13,
+		VerifyConnection: func(state tls.ConnectionState) error {
+			id, err := a.identify(state.PeerCertificates)
+			if err == nil && expectedID != "" && id != expectedID {
+				err = fmt.Errorf("certificate belongs to %s instead of %s", id, expectedID)
+			}
+			return err
+		},
+	})
+}
+
+// Returns the ID of the member that made the call, ok is false if the calls are not authenticated
+func (a *memberAuthenticator) caller(ctx context.Context) (id hashgraph.MemberID, ok bool) {
+	if a == nil {
+		return "", false
+	}
+	p, _ := peer.FromContext(ctx)
+	if p == nil {
+		return "", false
+	}
+	tlsInfo, isTLS := p.AuthInfo.(credentials.TLSInfo)
+	if !isTLS {
+		return "", false
+	}
+	id, err := a.identify(tlsInfo.State.PeerCertificates)
+	return id, err == nil
+}
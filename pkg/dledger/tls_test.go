@@ -0,0 +1,104 @@
+package dledger
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"../wire"
+)
+
+// Returns a new self-signed certificate with the given common name, which is valid for an hour
+func selfSignedCertificate(t *testing.T, commonName string) tls.Certificate {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Minute),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, publicKey, privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: privateKey}
+}
+
+// Connects to the member at the address with the given certificate, or without a certificate if it is empty
+func dialMember(t *testing.T, cluster *testCluster, address string, certificate tls.Certificate) *grpc.ClientConn {
+	authenticator := newMemberAuthenticator(&TLSConfig{Certificate: certificate}, cluster.peers)
+	conn, err := dialRPC(cluster.network, address, authenticator.clientCredentials(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return conn
+}
+
+// Clients connect without a certificate and can only call the transaction service, members can only send events in
+// their own name
+func TestOnlyMembersGossipInTheirOwnName(t *testing.T) {
+	cluster := startCluster(t, 4, 1000, true)
+	defer func() {
+		for _, member := range cluster.members {
+			_ = member.Stop()
+		}
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := DialTransactions(cluster.network, cluster.addresses[0], &TLSConfig{}, cluster.peers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = client.Close()
+	}()
+	if _, err := client.Nonce(cluster.ids[1]); err != nil {
+		t.Fatalf("client without a certificate can not call the transaction service: %s", err)
+	}
+	_, err = wire.NewGossipClient(client.conn).GetLatestEvents(ctx, &wire.GetLatestEventsRequest{Version: wire.ProtocolVersion})
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("client without a certificate gossiped, the error is %v", err)
+	}
+
+	stranger := dialMember(t, cluster, cluster.addresses[0], selfSignedCertificate(t, "Member 1"))
+	_, err = wire.NewGossipClient(stranger).GetLatestEvents(ctx, &wire.GetLatestEventsRequest{Version: wire.ProtocolVersion})
+	if err == nil {
+		t.Fatal("certificate that is not pinned for a member is accepted")
+	}
+
+	gossip := wire.NewGossipClient(dialMember(t, cluster, cluster.addresses[0], cluster.certificates[1]))
+	if _, err := gossip.GetLatestEvents(ctx, &wire.GetLatestEventsRequest{Version: wire.ProtocolVersion}); err != nil {
+		t.Fatalf("member can not gossip: %s", err)
+	}
+	request := &wire.SyncEventsRequest{Version: wire.ProtocolVersion, SenderId: string(cluster.ids[1])}
+	if _, err := gossip.SyncEvents(ctx, request); err != nil {
+		t.Fatalf("member can not send events in its own name: %s", err)
+	}
+	request.SenderId = string(cluster.ids[2])
+	if _, err := gossip.SyncEvents(ctx, request); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("member sent events in the name of another member, the error is %v", err)
+	}
+}
@@ -0,0 +1,254 @@
+package dledger
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"../hashgraph"
+	"../wire"
+)
+
+const (
+	TxUnknown   = "unknown"   // The transaction was not submitted to this member
+	TxPending   = "pending"   // The transaction is in the buffer of this member, waiting for the next event of this member
+	TxInEvent   = "in_event"  // The transaction is in an event of this member that did not reach consensus yet
+	TxConsensus = "consensus" // The event of the transaction reached consensus and the transaction is delivered to the ledger
+
+	statusRetentionRounds = 100 // The status of a transaction is forgotten this many rounds after it reaches consensus
+)
+
+//TransactionStatus : Where a submitted transaction is on its way to consensus
+type TransactionStatus struct {
+	ID                 string    `json:"id"`                            // ID of the transaction
+	State              string    `json:"state"`                         // One of TxUnknown, TxPending, TxInEvent or TxConsensus
+	EventHash          string    `json:"event_hash,omitempty"`          // Hash of the event that includes the transaction
+	RoundReceived      uint32    `json:"round_received,omitempty"`      // Only when the transaction reached consensus
+	ConsensusTimestamp time.Time `json:"consensus_timestamp,omitempty"` // Only when the transaction reached consensus
+	Applied            bool      `json:"applied,omitempty"`             // Only when the transaction reached consensus, false if the ledger rejected it
+}
+
+//TransactionID : Returns the ID of a transaction, which is the hex encoded SHA256 hash of the transaction
+func TransactionID(tx hashgraph.Transaction) string {
+	hash := sha256.Sum256(tx)
+	return hex.EncodeToString(hash[:])
+}
+
+//Tracker : The application of the hashgraph of a member. It applies transactions to the ledger and records the
+// consensus results of the transactions that are submitted to the member, so that their status can be queried.
+type Tracker struct {
+	*Ledger
+	mutex     sync.Mutex
+	submitted map[string]*TransactionStatus // map of transaction ID -> status of the transactions submitted to me
+}
+
+//NewTracker : Creates a tracker that applies transactions to the given ledger
+func NewTracker(ledger *Ledger) *Tracker {
+	return &Tracker{
+		Ledger:    ledger,
+		submitted: make(map[string]*TransactionStatus),
+	}
+}
+
+//DeliverTx : Applies the transaction to the ledger, and records the result of its first delivery if the transaction was
+// submitted to me
+func (t *Tracker) DeliverTx(tx hashgraph.Transaction, e *hashgraph.Event) {
+	applied := t.Ledger.apply(tx)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.submitted) == 0 {
+		return
+	}
+	if status, ok := t.submitted[TransactionID(tx)]; ok && status.State != TxConsensus {
+		status.State = TxConsensus
+		status.EventHash = e.Hash()
+		status.RoundReceived = e.RoundReceived
+		status.ConsensusTimestamp = e.ConsensusTimestamp
+		status.Applied = applied
+	}
+}
+
+//Commit : Commits the ledger at the end of the round, and forgets the statuses of the transactions that reached
+// consensus more than statusRetentionRounds rounds ago, their status is TxUnknown afterwards
+func (t *Tracker) Commit(round uint32) {
+	t.Ledger.Commit(round)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if round <= statusRetentionRounds {
+		return
+	}
+	for id, status := range t.submitted {
+		if status.State == TxConsensus && status.RoundReceived < round-statusRetentionRounds {
+			delete(t.submitted, id)
+		}
+	}
+}
+
+// Starts tracking a transaction, returns false if it is already tracked
+func (t *Tracker) track(id string) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, ok := t.submitted[id]; ok {
+		return false
+	}
+	t.submitted[id] = &TransactionStatus{ID: id, State: TxPending}
+	return true
+}
+
+// Returns a copy of the recorded status of a transaction, ok is false if the transaction is not tracked
+func (t *Tracker) status(id string) (status TransactionStatus, ok bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	recorded, ok := t.submitted[id]
+	if !ok {
+		return TransactionStatus{ID: id, State: TxUnknown}, false
+	}
+	return *recorded, true
+}
+
+//TransactionService : gRPC service that clients submit their transfers to and query the status of their transfers from
+type TransactionService struct {
+	wire.UnimplementedTransactionsServer
+	dl *DLedger
+}
+
+//Submit : Adds a transfer signed by its sender to the buffer of the member, replies with the ID of the transaction
+func (s *TransactionService) Submit(_ context.Context, request *wire.SubmitRequest) (*wire.SubmitResponse, error) {
+	if err := wire.CheckVersion(request.GetVersion()); err != nil {
+		return nil, err
+	}
+	id, err := s.dl.SubmitTransfer(transferFromWire(request.GetTransfer()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &wire.SubmitResponse{Version: wire.ProtocolVersion, Id: id}, nil
+}
+
+//Nonce : Replies with the nonce of the last committed transfer of the member with the given ID, the next transfer of
+// the member must have the next nonce
+func (s *TransactionService) Nonce(_ context.Context, request *wire.NonceRequest) (*wire.NonceResponse, error) {
+	if err := wire.CheckVersion(request.GetVersion()); err != nil {
+		return nil, err
+	}
+	nonce := s.dl.Ledger.Nonce(hashgraph.MemberID(request.GetMemberId()))
+	return &wire.NonceResponse{Version: wire.ProtocolVersion, Nonce: nonce}, nil
+}
+
+//Status : Replies with the status of the transaction with the given ID
+func (s *TransactionService) Status(_ context.Context, request *wire.StatusRequest) (*wire.StatusResponse, error) {
+	if err := wire.CheckVersion(request.GetVersion()); err != nil {
+		return nil, err
+	}
+	txStatus := s.dl.TransactionStatus(request.GetId())
+	return &wire.StatusResponse{Version: wire.ProtocolVersion, Status: statusToWire(txStatus)}, nil
+}
+
+//TransactionClient : Client of the transaction service of a member
+type TransactionClient struct {
+	conn   *grpc.ClientConn
+	client wire.TransactionsClient
+}
+
+//DialTransactions : Connects to the transaction service of the member at the given address. The certificate of the
+// member is verified with the peers and the CAs in tlsConfig, which needs no certificate of its own since clients are
+// not members. tlsConfig is nil for plaintext connections.
+func DialTransactions(transport Transport, address string, tlsConfig *TLSConfig, peers map[hashgraph.MemberID]Peer) (*TransactionClient, error) {
+	conn, err := dialRPC(transport, address, newMemberAuthenticator(tlsConfig, peers).clientCredentials(""))
+	if err != nil {
+		return nil, err
+	}
+	return &TransactionClient{conn: conn, client: wire.NewTransactionsClient(conn)}, nil
+}
+
+//Submit : Submits a transfer signed by its sender, returns the ID of the transaction
+func (c *TransactionClient) Submit(transfer Transfer) (string, error) {
+	response, err := c.client.Submit(context.Background(), &wire.SubmitRequest{Version: wire.ProtocolVersion, Transfer: transferToWire(transfer)})
+	if err != nil {
+		return "", err
+	}
+	return response.GetId(), wire.CheckVersion(response.GetVersion())
+}
+
+//Nonce : Returns the nonce of the last committed transfer of the member with the given ID
+func (c *TransactionClient) Nonce(id hashgraph.MemberID) (uint64, error) {
+	response, err := c.client.Nonce(context.Background(), &wire.NonceRequest{Version: wire.ProtocolVersion, MemberId: string(id)})
+	if err != nil {
+		return 0, err
+	}
+	return response.GetNonce(), wire.CheckVersion(response.GetVersion())
+}
+
+//Status : Returns the status of the transaction with the given ID
+func (c *TransactionClient) Status(id string) (TransactionStatus, error) {
+	response, err := c.client.Status(context.Background(), &wire.StatusRequest{Version: wire.ProtocolVersion, Id: id})
+	if err != nil {
+		return TransactionStatus{}, err
+	}
+	return statusFromWire(response.GetStatus()), wire.CheckVersion(response.GetVersion())
+}
+
+//Close : Closes the connection to the member
+func (c *TransactionClient) Close() error {
+	return c.conn.Close()
+}
+
+func transferToWire(t Transfer) *wire.Transfer {
+	return &wire.Transfer{
+		SenderId:   string(t.SenderID),
+		ReceiverId: string(t.ReceiverID),
+		Amount:     t.Amount,
+		Nonce:      t.Nonce,
+		Signature:  t.Signature,
+	}
+}
+
+func transferFromWire(t *wire.Transfer) Transfer {
+	return Transfer{
+		SenderID:   hashgraph.MemberID(t.GetSenderId()),
+		ReceiverID: hashgraph.MemberID(t.GetReceiverId()),
+		Amount:     t.GetAmount(),
+		Nonce:      t.GetNonce(),
+		Signature:  t.GetSignature(),
+	}
+}
+
+func statusToWire(s TransactionStatus) *wire.TransactionStatus {
+	txStatus := &wire.TransactionStatus{
+		Id:            s.ID,
+		State:         s.State,
+		EventHash:     s.EventHash,
+		RoundReceived: s.RoundReceived,
+		Applied:       s.Applied,
+	}
+	if s.State == TxConsensus {
+		txStatus.ConsensusTimestamp = timestamppb.New(s.ConsensusTimestamp)
+	}
+	return txStatus
+}
+
+func statusFromWire(s *wire.TransactionStatus) TransactionStatus {
+	txStatus := TransactionStatus{
+		ID:            s.GetId(),
+		State:         s.GetState(),
+		EventHash:     s.GetEventHash(),
+		RoundReceived: s.GetRoundReceived(),
+		Applied:       s.GetApplied(),
+	}
+	if s.GetConsensusTimestamp() != nil {
+		txStatus.ConsensusTimestamp = s.GetConsensusTimestamp().AsTime()
+	}
+	return txStatus
+}
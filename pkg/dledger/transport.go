@@ -0,0 +1,142 @@
+package dledger
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+//Transport : Carries the connections between members. Members serve the gossip calls of their peers with gRPC over
+// the connections of their transport, so members can reach each other over TCP or within one process.
+type Transport interface {
+	Listen(address string) (net.Listener, error) // Accepts the connections that peers dial to the address
+	Dial(address string) (net.Conn, error)       // Connects to the member listening on the address
+}
+
+//TCPTransport : Transport over TCP, addresses are ip:port
+type TCPTransport struct{}
+
+//Listen : Listens on the TCP address
+func (TCPTransport) Listen(address string) (net.Listener, error) {
+	return net.Listen("tcp", address)
+}
+
+//Dial : Connects to the TCP address
+func (TCPTransport) Dial(address string) (net.Conn, error) {
+	return net.Dial("tcp", address)
+}
+
+//MemoryNetwork : Transport between members in the same process, which pass connections to each other through
+// channels instead of sockets. Addresses are arbitrary names, so a test can run many members side by side.
+type MemoryNetwork struct {
+	sync.Mutex
+	listeners map[string]*memoryListener // map of address -> listener on that address
+}
+
+//ErrAddressInUse : Returned when a member listens on an address of a memory network that is already listened on
+var ErrAddressInUse = errors.New("address is already in use")
+
+//ErrConnectionRefused : Returned when a member dials an address of a memory network that nobody listens on
+var ErrConnectionRefused = errors.New("connection refused")
+
+//NewMemoryNetwork : Creates a memory network without any members
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{listeners: make(map[string]*memoryListener)}
+}
+
+//Listen : Listens on the address of the network
+func (m *MemoryNetwork) Listen(address string) (net.Listener, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if _, ok := m.listeners[address]; ok {
+		return nil, ErrAddressInUse
+	}
+	listener := &memoryListener{
+		network: m,
+		address: memoryAddr(address),
+		conns:   make(chan net.Conn),
+		closed:  make(chan struct{}),
+	}
+	m.listeners[address] = listener
+	return listener, nil
+}
+
+//Dial : Connects to the member listening on the address of the network, with one end of an in-memory pipe
+func (m *MemoryNetwork) Dial(address string) (net.Conn, error) {
+	m.Lock()
+	listener, ok := m.listeners[address]
+	m.Unlock()
+	if !ok {
+		return nil, ErrConnectionRefused
+	}
+
+	clientConn, serverConn := net.Pipe()
+	select {
+	case listener.conns <- serverConn:
+		return clientConn, nil
+	case <-listener.closed:
+		_ = clientConn.Close()
+		_ = serverConn.Close()
+		return nil, ErrConnectionRefused
+	}
+}
+
+// Listener of a memory network, which receives the server ends of the pipes from the members that dial it
+type memoryListener struct {
+	network   *MemoryNetwork
+	address   memoryAddr
+	conns     chan net.Conn // server ends of the pipes that are dialed
+	closed    chan struct{} // closed when the listener is closed
+	closeOnce sync.Once
+}
+
+// Waits for a member to dial the address
+func (l *memoryListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+// Stops listening and frees the address
+func (l *memoryListener) Close() error {
+	l.closeOnce.Do(func() {
+		l.network.Lock()
+		delete(l.network.listeners, string(l.address))
+		l.network.Unlock()
+		close(l.closed)
+	})
+	return nil
+}
+
+func (l *memoryListener) Addr() net.Addr {
+	return l.address
+}
+
+// Address of a memory network
+type memoryAddr string
+
+func (a memoryAddr) Network() string {
+	return "memory"
+}
+
+func (a memoryAddr) String() string {
+	return string(a)
+}
+
+// Returns a gRPC client connection to the member listening on the address, which connects through the transport on
+// its first call and secures the connection with the given credentials
+func dialRPC(transport Transport, address string, creds credentials.TransportCredentials) (*grpc.ClientConn, error) {
+	return grpc.NewClient("passthrough:///"+address,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithContextDialer(func(_ context.Context, address string) (net.Conn, error) {
+			return transport.Dial(address)
+		}))
+}
@@ -0,0 +1,27 @@
+package hashgraph
+
+//Application : The state machine on top of the hashgraph. Transactions are opaque to the hashgraph, the application
+// receives them in consensus order, which is the same on every member, so every member arrives at the same state.
+type Application interface {
+	DeliverTx(tx Transaction, e *Event) // Called for every transaction of an event that reached consensus, in consensus order
+	Commit(round uint32)                // Called after the transactions of all events received in the round are delivered
+	SnapshotState() []byte              // Returns the state after the last commit, which is included in snapshots
+	RestoreState(state []byte) error    // Replaces the state with the state of an installed snapshot
+}
+
+// Delivers the transactions of an event that reached consensus to the application of the node if it has one
+func (n *Node) deliver(e *Event) {
+	if n.Application == nil {
+		return
+	}
+	for _, tx := range e.Transactions {
+		n.Application.DeliverTx(tx, e)
+	}
+}
+
+// Tells the application of the node that all events received in the round are delivered
+func (n *Node) commit(round uint32) {
+	if n.Application != nil {
+		n.Application.Commit(round)
+	}
+}
@@ -0,0 +1,87 @@
+package hashgraph
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+)
+
+// Builds a hashgraph event by event. Members sign their events with keys derived from fixed seeds and stamp them with
+// a fake clock, so that the events, and the coin flips that depend on their signatures, are the same on every run.
+type testDAG struct {
+	keys   []ed25519.PrivateKey
+	ids    []MemberID
+	node   *Node     // node of the first member that the events are inserted to as they are created
+	latest []*Event  // latest event of each member
+	events []*Event  // all events in the order they are created
+	clock  time.Time // timestamp of the latest event
+}
+
+// Creates the initial events of count members
+func newTestDAG(t *testing.T, count int, coinRoundFrequency uint32) *testDAG {
+	d := &testDAG{clock: time.Unix(1600000000, 0).UTC()}
+	for i := 0; i < count; i++ {
+		seed := make([]byte, ed25519.SeedSize)
+		seed[0] = byte(i + 1)
+		key := ed25519.NewKeyFromSeed(seed)
+		d.keys = append(d.keys, key)
+		d.ids = append(d.ids, NewMemberID(key.Public().(ed25519.PublicKey)))
+	}
+	d.node = d.newNode(coinRoundFrequency)
+	d.latest = make([]*Event, count)
+	for i := 0; i < count; i++ {
+		d.add(t, i, -1)
+	}
+	return d
+}
+
+// Creates a node of the first member that knows no events. Events are never pruned, so that every event can be checked.
+func (d *testDAG) newNode(coinRoundFrequency uint32) *Node {
+	return d.newMemberNode(0, coinRoundFrequency)
+}
+
+// Creates a node of the member that knows no events, events are never pruned
+func (d *testDAG) newMemberNode(member int, coinRoundFrequency uint32) *Node {
+	initialHashgraph := make(map[MemberID][]*Event)
+	for _, id := range d.ids {
+		initialHashgraph[id] = nil
+	}
+	n := NewNode(initialHashgraph, d.keys[member], nil)
+	n.CoinRoundFrequency = coinRoundFrequency
+	n.RetentionRounds = 1000
+	return n
+}
+
+// Creates an event of the creator whose other parent is the latest event of the other parent member, or an initial
+// event if otherParent is negative, then inserts it to the node of the DAG
+func (d *testDAG) add(t *testing.T, creator int, otherParent int) *Event {
+	d.clock = d.clock.Add(time.Millisecond)
+	e := &Event{Owner: d.ids[creator], Timestamp: d.clock, Transactions: []Transaction{[]byte(d.clock.String())}}
+	if otherParent >= 0 {
+		e.SelfParentHash = d.latest[creator].Hash()
+		e.OtherParentHash = d.latest[otherParent].Hash()
+	}
+	e.Sign(d.keys[creator])
+	d.latest[creator] = e
+	d.events = append(d.events, e)
+	receiveEvents(t, d.node, []*Event{e})
+	return e
+}
+
+// Inserts the events to the node and runs the consensus on them, like a sync does
+func receiveEvents(t *testing.T, n *Node, events []*Event) {
+	copies := make([]*Event, len(events))
+	for i, e := range events {
+		c := *e
+		copies[i] = &c
+	}
+	if _, err := n.insertReceivedEvents(copies); err != nil {
+		t.Fatal(err)
+	}
+	n.DecideFame()
+	n.FindOrder()
+	n.PruneEvents()
+	if err := n.flushRecords(); err != nil {
+		t.Fatal(err)
+	}
+}
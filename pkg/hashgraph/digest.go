@@ -0,0 +1,96 @@
+package hashgraph
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+)
+
+// Consensus of a round as it is hashed into the consensus digest. Events are sorted by hash instead of consensus order,
+// the order follows from their consensus timestamps and the famous witnesses.
+type roundConsensus struct {
+	Previous        string          `json:"previous"`         // Consensus digest after the previous round
+	Round           uint32          `json:"round"`            // Round that the events are received in
+	FamousWitnesses []string        `json:"famous_witnesses"` // Hashes of the famous witnesses of the round
+	ReceivedEvents  []receivedEvent `json:"received_events"`  // Events that are received in the round
+}
+
+// State of an event that reached consensus, which every member calculates the same
+type receivedEvent struct {
+	Hash               string `json:"hash"`
+	Round              uint32 `json:"round"`
+	IsWitness          bool   `json:"is_witness"`
+	ConsensusTimestamp int64  `json:"consensus_timestamp"` // Unix time in nanoseconds, independent of the time zone
+}
+
+// Returns the consensus digest after the round, given the digest after the previous round. Every member arrives at the
+// same famous witnesses and the same received events in a round, so their digests are the same.
+func chainConsensusDigest(previous string, round uint32, famousWitnesses []*Event, receivedEvents []*Event) string {
+	consensus := roundConsensus{Previous: previous, Round: round, FamousWitnesses: []string{}, ReceivedEvents: []receivedEvent{}}
+	for _, w := range famousWitnesses {
+		consensus.FamousWitnesses = append(consensus.FamousWitnesses, w.Hash())
+	}
+	sort.Strings(consensus.FamousWitnesses)
+	for _, e := range receivedEvents {
+		consensus.ReceivedEvents = append(consensus.ReceivedEvents, receivedEvent{
+			Hash:               e.Hash(),
+			Round:              e.Round,
+			IsWitness:          e.IsWitness,
+			ConsensusTimestamp: e.ConsensusTimestamp.UnixNano(),
+		})
+	}
+	sort.Slice(consensus.ReceivedEvents, func(i, j int) bool {
+		return consensus.ReceivedEvents[i].Hash < consensus.ReceivedEvents[j].Hash
+	})
+	encoded, err := json.Marshal(consensus)
+	handleError(err)
+	hash := sha256.Sum256(encoded)
+	return hex.EncodeToString(hash[:])
+}
+
+// Chains the consensus of the round that I just received to my consensus digest, the fame of the witnesses of the round
+// is decided
+func (n *Node) advanceConsensusDigest(round uint32, receivedEvents []*Event) {
+	var famousWitnesses []*Event
+	for _, w := range n.findWitnessesOfARound(round) {
+		if w.IsFamous {
+			famousWitnesses = append(famousWitnesses, w)
+		}
+	}
+	n.consensusDigests[round] = chainConsensusDigest(n.consensusDigests[round-1], round, famousWitnesses, receivedEvents)
+}
+
+// Calculates the consensus digest after the last round received of the snapshot from the states of its events,
+// starting from the digest after its base round. Famous witnesses and received events of the rounds after the base
+// round are never pruned, so they are all in the snapshot.
+func (s *Snapshot) replayConsensusDigest() string {
+	famousWitnesses := make(map[uint32][]*Event)
+	receivedEvents := make(map[uint32][]*Event)
+	for i := range s.Events {
+		e := s.Events[i].Event
+		e.eventState = s.Events[i].State
+		if e.IsWitness && e.IsFamous && e.Round > s.DigestBaseRound && e.Round <= s.LastRoundReceived {
+			famousWitnesses[e.Round] = append(famousWitnesses[e.Round], &e)
+		}
+		if e.RoundReceived > s.DigestBaseRound && e.RoundReceived <= s.LastRoundReceived {
+			receivedEvents[e.RoundReceived] = append(receivedEvents[e.RoundReceived], &e)
+		}
+	}
+	digest := s.DigestBase
+	for round := s.DigestBaseRound + 1; round <= s.LastRoundReceived; round++ {
+		digest = chainConsensusDigest(digest, round, famousWitnesses[round], receivedEvents[round])
+	}
+	return digest
+}
+
+// Returns the earliest round whose consensus digest I still know, the snapshots that I take start from it
+func (n *Node) consensusDigestBaseRound() uint32 {
+	baseRound := n.lastRoundReceived
+	for round := range n.consensusDigests {
+		if round < baseRound {
+			baseRound = round
+		}
+	}
+	return baseRound
+}
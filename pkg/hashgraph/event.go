@@ -1,15 +1,29 @@
 package hashgraph
 
-import "time"
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"time"
+)
 
-//Event : An event of hashgraph
+//Event : An event of hashgraph as it is created and signed by its owner. Only these fields are sent to the peers and
+// stored, the consensus fields in the state of the event are calculated by every member on its own.
 type Event struct {
-	Owner              string        `json:"owner"`               // Address of the node that created this event
-	Signature          string        `json:"signature"`           // Event should be signed by it's creator
-	SelfParentHash     string        `json:"self_parent_hash"`    // Hash of the self-parent, which is the hash for the event before this event in my timeline.
-	OtherParentHash    string        `json:"other_parent_hash"`   // Hash of the other-parent, which is the hash for the last event of the peer that called me.
-	Timestamp          time.Time     `json:"timestamp"`           // Datetime of creation
-	Transactions       []Transaction `json:"transactions"`        // List of transactions for this event, size can be 0 too.
+	Owner           MemberID      `json:"owner"`             // ID of the member that created this event
+	Signature       string        `json:"signature"`         // Event should be signed by it's creator
+	SelfParentHash  string        `json:"self_parent_hash"`  // Hash of the self-parent, which is the hash for the event before this event in my timeline.
+	OtherParentHash string        `json:"other_parent_hash"` // Hash of the other-parent, which is the hash for the last event of the peer that called me.
+	Timestamp       time.Time     `json:"timestamp"`         // Datetime of creation
+	Transactions    []Transaction `json:"transactions"`      // List of transactions for this event, size can be 0 too.
+	eventState      `json:"-"`    // Local state of the event, its fields are promoted but neither gob nor json encodes it
+	hash            string        // Cached result of Hash(), never sent over the wire
+}
+
+//EventState : Consensus fields of an event, which are derived from the hashgraph by every member on its own. A peer can
+// not send these fields, so a byzantine peer can not make me trust its consensus.
+type EventState struct {
 	Round              uint32        `json:"round"`               // Calculated by divideRounds(),  initial event is round 1
 	IsWitness          bool          `json:"is_witness"`          // Is this event the first event in it's round at it's member?
 	IsFamous           bool          `json:"is_famous"`           // Is this witness a famous witness?
@@ -17,4 +31,69 @@ type Event struct {
 	RoundReceived      uint32        `json:"round_received"`      // Consensus round
 	ConsensusTimestamp time.Time     `json:"consensus_timestamp"` // Timestamp assigned by the consensus
 	Latency            time.Duration `json:"latency"`             // How long did it take for this event to reach to a consensus
+	whitenedSignature  []byte        // Signature XORed with the signatures of the famous witnesses of the round received, breaks ties in consensus order
+}
+
+// EventState is embedded in Event under this unexported name, so that it is not sent over the wire
+type eventState = EventState
+
+//State : Returns a copy of the local state of the event
+func (e *Event) State() EventState {
+	return e.eventState
+}
+
+//Hash : Returns the hex encoded SHA-256 hash of the canonical encoding of the event, which identifies the event.
+// Consensus fields and the signature are not part of the hash, so every member computes the same hash for an event.
+func (e *Event) Hash() string {
+	if e.hash == "" {
+		sum := sha256.Sum256(e.canonicalBytes())
+		e.hash = hex.EncodeToString(sum[:])
+	}
+	return e.hash
+}
+
+//Sign : Signs the canonical encoding of the event with the creator's private key
+func (e *Event) Sign(privateKey ed25519.PrivateKey) {
+	e.Signature = hex.EncodeToString(ed25519.Sign(privateKey, e.canonicalBytes()))
+}
+
+//VerifySignature : Returns true if the event's signature was produced by the owner of the given public key
+func (e *Event) VerifySignature(publicKey ed25519.PublicKey) bool {
+	signature, err := hex.DecodeString(e.Signature)
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return false
+	}
+	return ed25519.Verify(publicKey, e.canonicalBytes(), signature)
+}
+
+// Clears the local state of the event, which is calculated again by me
+func (e *Event) resetState() {
+	e.eventState = EventState{ConsensusTimestamp: time.Unix(0, 0)}
+}
+
+// Deterministic byte encoding of the signed content of an event. Only the fields set by the creator are included,
+// consensus fields are calculated locally by every member and are not signed.
+func (e *Event) canonicalBytes() []byte {
+	var buf []byte
+	buf = appendString(buf, string(e.Owner))
+	buf = appendString(buf, e.SelfParentHash)
+	buf = appendString(buf, e.OtherParentHash)
+	buf = appendUint64(buf, uint64(e.Timestamp.UnixNano()))
+	buf = appendUint64(buf, uint64(len(e.Transactions)))
+	for _, t := range e.Transactions {
+		buf = appendString(buf, string(t))
+	}
+	return buf
+}
+
+// Strings are length prefixed so that different field splits can not produce the same encoding
+func appendString(buf []byte, s string) []byte {
+	buf = appendUint64(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
 }
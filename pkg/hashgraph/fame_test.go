@@ -0,0 +1,56 @@
+package hashgraph
+
+import "testing"
+
+// Gossips of 5 members that keep the votes about the fame of the round 1 witness of the first member split. Only the
+// witnesses of the first two members of round 2 see it, so they vote yes and the other three vote no. In every later
+// round, the witnesses of the first two members do not strongly see the witness of the last member of the previous
+// round, so they see a tie and vote yes, while the others strongly see all witnesses and vote no. No witness ever sees a
+// supermajority, so normal voting never decides the fame.
+var stalledElection = [][2]int{
+	{3, 4}, {3, 4}, {2, 3}, {3, 2}, {1, 4}, {4, 2}, {2, 3}, {1, 3}, {4, 1}, {0, 3}, {3, 4}, {1, 0}, {0, 1}, {2, 3},
+	{3, 4}, {0, 2}, {1, 4}, {3, 2}, {4, 2}, {1, 2}, {1, 3}, {2, 1}, {3, 2}, {1, 2}, {1, 2}, {3, 1}, {1, 0}, {4, 0},
+	{1, 3}, {0, 3}, {2, 4}, {0, 4}, {3, 1}, {1, 4}, {1, 0}, {0, 3}, {2, 3}, {2, 1}, {1, 3}, {3, 1}, {4, 3}, {2, 0},
+	{4, 2}, {3, 0}, {2, 3}, {0, 4}, {3, 4}, {0, 4}, {0, 1}, {1, 0}, {3, 2}, {3, 2}, {2, 1}, {4, 2}, {0, 3}, {3, 1},
+	{1, 4}, {2, 3}, {3, 0}, {4, 2}, {2, 0}, {4, 3}, {1, 0}, {2, 1}, {0, 1}, {1, 0}, {1, 4}, {3, 4}, {3, 1}, {4, 3},
+	{0, 1}, {2, 3}, {4, 3}, {3, 0}, {2, 3}, {4, 1}, {3, 2}, {3, 4}, {4, 3}, {1, 0}, {2, 0}, {2, 4}, {1, 0}, {3, 0},
+	{4, 0}, {1, 4}, {3, 0}, {0, 4}, {3, 2}, {3, 1}, {2, 3}, {4, 3}, {3, 0}, {1, 3}, {3, 2}, {4, 3}, {3, 1}, {2, 4},
+	{0, 2}, {1, 3}, {3, 4}, {4, 3}, {1, 3}, {4, 0}, {2, 1}, {3, 2}, {0, 1}, {2, 0}, {2, 3}, {1, 4}, {1, 2}, {0, 4},
+	{0, 4}, {4, 2}, {3, 2}, {0, 1}, {3, 2}, {3, 4}, {1, 4}, {2, 0}, {3, 2}, {4, 2}, {3, 1}, {1, 3}, {0, 4}, {1, 0},
+	{1, 3}, {4, 0}, {4, 3}, {3, 4}, {4, 1}, {0, 4}, {4, 3}, {3, 1}, {1, 0}, {2, 1}, {3, 4}, {4, 2}, {3, 0},
+}
+
+// Builds the stalled election, returns the round 1 witness of the first member
+func buildStalledElection(t *testing.T, coinRoundFrequency uint32) (*testDAG, *Event) {
+	d := newTestDAG(t, 5, coinRoundFrequency)
+	candidate := d.latest[0]
+	for _, gossip := range stalledElection {
+		d.add(t, gossip[0], gossip[1])
+	}
+	return d, d.node.Events[candidate.Hash()]
+}
+
+func TestFameStallsWithoutCoinRounds(t *testing.T) {
+	d, candidate := buildStalledElection(t, 0)
+	if round := d.node.Events[d.latest[0].Hash()].Round; round < 9 {
+		t.Fatalf("hashgraph reached round %d, expected at least 9", round)
+	}
+	if candidate.IsFameDecided {
+		t.Fatal("fame of the candidate is decided without coin rounds")
+	}
+	if len(d.node.ConsensusEvents) != 0 {
+		t.Fatalf("%d events reached consensus without deciding the fame of a round 1 witness", len(d.node.ConsensusEvents))
+	}
+}
+
+func TestCoinRoundsDecideStalledFame(t *testing.T) {
+	for _, frequency := range []uint32{2, 3, 4} {
+		d, candidate := buildStalledElection(t, frequency)
+		if !candidate.IsFameDecided {
+			t.Fatalf("fame of the candidate is not decided with a coin round every %d rounds", frequency)
+		}
+		if len(d.node.ConsensusEvents) == 0 {
+			t.Fatalf("no event reached consensus with a coin round every %d rounds", frequency)
+		}
+	}
+}
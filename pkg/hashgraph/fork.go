@@ -0,0 +1,76 @@
+package hashgraph
+
+import (
+	"crypto/ed25519"
+	"sort"
+)
+
+//ForkProof : Two events created by the same member with the same self-parent. Since both events are signed by their
+// creator, anyone with the creator's public key can verify that the creator forked.
+type ForkProof struct {
+	First  *Event `json:"first"`  // The event that was inserted first
+	Second *Event `json:"second"` // The event that conflicts with the first one
+}
+
+//Verify : Returns true if both events are signed with the given key and they are conflicting
+func (f ForkProof) Verify(publicKey ed25519.PublicKey) bool {
+	return f.First.Owner == f.Second.Owner &&
+		f.First.SelfParentHash == f.Second.SelfParentHash &&
+		f.First.Hash() != f.Second.Hash() &&
+		f.First.VerifySignature(publicKey) &&
+		f.Second.VerifySignature(publicKey)
+}
+
+// Events of a member are identified by their self-parent in a member's timeline, two events with the same key is a fork
+type selfParentKey struct {
+	owner          MemberID
+	selfParentHash string // empty for the initial event
+}
+
+//Forkers : Returns the IDs of the members that are detected to have forked, in sorted order
+func (n *Node) Forkers() []MemberID {
+	n.RWMutex.RLock()
+	defer n.RWMutex.RUnlock()
+
+	forkers := make([]MemberID, 0, len(n.Forks))
+	for id := range n.Forks {
+		forkers = append(forkers, id)
+	}
+	sort.Slice(forkers, func(i, j int) bool { return forkers[i] < forkers[j] })
+	return forkers
+}
+
+//ForkProofs : Returns the proofs of the forks that the member with given ID made
+func (n *Node) ForkProofs(id MemberID) []ForkProof {
+	n.RWMutex.RLock()
+	defer n.RWMutex.RUnlock()
+
+	return append([]ForkProof(nil), n.Forks[id]...)
+}
+
+// Checks if the new event conflicts with an event of the same member that is already known, records a proof if it does
+func (n *Node) detectFork(e *Event) {
+	key := selfParentKey{owner: e.Owner, selfParentHash: e.SelfParentHash}
+	existing, ok := n.selfChildren[key]
+	if !ok {
+		n.selfChildren[key] = e
+		return
+	}
+	if existing.Hash() == e.Hash() {
+		return
+	}
+
+	n.Forks[e.Owner] = append(n.Forks[e.Owner], ForkProof{First: existing, Second: e})
+	// Cached results may have assumed that the timeline of this member is a chain
+	n.seeDPMemory = make(map[string]map[string]bool)
+}
+
+// Returns true if current has ancestors on both sides of a fork of the given member
+func (n *Node) seesForkBy(current *Event, id MemberID) bool {
+	for _, proof := range n.Forks[id] {
+		if n.ancestor(current, proof.First) && n.ancestor(current, proof.Second) {
+			return true
+		}
+	}
+	return false
+}
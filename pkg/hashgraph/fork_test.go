@@ -0,0 +1,63 @@
+package hashgraph
+
+import (
+	"crypto/ed25519"
+	"testing"
+	"time"
+)
+
+// A member that signs two events with the same self-parent is reported as a forker with a proof that anyone can
+// verify, and an event that has both branches of the fork as ancestors no longer sees the events of the forker
+func TestForkIsDetectedAndForkerIsNotSeen(t *testing.T) {
+	d := newTestDAG(t, 4, defaultCoinRoundFrequency)
+	forker := 1
+	selfParent := d.latest[forker]
+
+	// First branch, learned by member 0
+	first := d.add(t, forker, 0)
+	beforeFork := d.add(t, 0, forker)
+
+	// Second branch with the same self-parent, learned by member 2
+	d.clock = d.clock.Add(time.Millisecond)
+	second := &Event{Owner: d.ids[forker], Timestamp: d.clock, Transactions: []Transaction{[]byte("fork")},
+		SelfParentHash: selfParent.Hash(), OtherParentHash: d.latest[3].Hash()}
+	second.Sign(d.keys[forker])
+	d.latest[forker] = second
+	d.events = append(d.events, second)
+	receiveEvents(t, d.node, []*Event{second})
+	d.add(t, 2, forker)
+
+	// Member 0 learns the second branch through member 2
+	afterFork := d.add(t, 0, 2)
+
+	forkers := d.node.Forkers()
+	if len(forkers) != 1 || forkers[0] != d.ids[forker] {
+		t.Fatalf("forkers are %v, expected only %s", forkers, d.ids[forker])
+	}
+	proofs := d.node.ForkProofs(d.ids[forker])
+	if len(proofs) != 1 || proofs[0].First.Hash() != first.Hash() || proofs[0].Second.Hash() != second.Hash() {
+		t.Fatalf("fork proofs of the forker are %v", proofs)
+	}
+	if !proofs[0].Verify(d.keys[forker].Public().(ed25519.PublicKey)) {
+		t.Fatal("fork proof does not verify with the key of the forker")
+	}
+	if proofs[0].Verify(d.keys[0].Public().(ed25519.PublicKey)) {
+		t.Fatal("fork proof verifies with the key of another member")
+	}
+	if len(d.node.ForkProofs(d.ids[0])) != 0 {
+		t.Fatal("an honest member has fork proofs")
+	}
+
+	firstInNode := d.node.Events[first.Hash()]
+	secondInNode := d.node.Events[second.Hash()]
+	if !d.node.see(d.node.Events[beforeFork.Hash()], firstInNode) {
+		t.Fatal("an event that has only one branch as an ancestor does not see it")
+	}
+	afterForkInNode := d.node.Events[afterFork.Hash()]
+	if d.node.see(afterForkInNode, firstInNode) || d.node.see(afterForkInNode, secondInNode) {
+		t.Fatal("an event that has both branches of the fork as ancestors sees the forker")
+	}
+	if !d.node.see(afterForkInNode, d.node.Events[d.latest[2].Hash()]) {
+		t.Fatal("an event does not see an honest member after the fork")
+	}
+}
@@ -0,0 +1,128 @@
+package hashgraph
+
+import "fmt"
+
+const (
+	maxOrphanCount = 10000 // orphans beyond this many are dropped, peers send them again once their parents are known
+)
+
+// Inserts the received events in topological order, so that the parents of an event are always inserted before the
+// event. Events whose parents are not known yet are buffered as orphans until their parents arrive. Returns false if
+// some of the received events are left with parents that are neither known nor buffered, which means that the sender
+// pruned those parents, so I can only catch up with a snapshot.
+func (n *Node) insertReceivedEvents(receivedEvents []*Event) (bool, error) {
+	for _, e := range receivedEvents {
+		if _, known := n.Events[e.Hash()]; !known && !n.isPrunedInitialEvent(e) {
+			n.orphans[e.Hash()] = e
+		}
+	}
+
+	// Index the orphans by their parents, and start with the orphans whose parents are known
+	var ready []*Event
+	children := make(map[string][]*Event)
+	for hash, e := range n.orphans {
+		if _, known := n.Events[hash]; known {
+			delete(n.orphans, hash) // learned from another peer or a snapshot
+			continue
+		}
+		if n.parentsKnown(e) {
+			ready = append(ready, e)
+			continue
+		}
+		children[e.SelfParentHash] = append(children[e.SelfParentHash], e)
+		if e.OtherParentHash != e.SelfParentHash {
+			children[e.OtherParentHash] = append(children[e.OtherParentHash], e)
+		}
+	}
+
+	for len(ready) > 0 {
+		e := ready[0]
+		ready = ready[1:]
+		if _, orphan := n.orphans[e.Hash()]; !orphan {
+			continue // both parents were inserted in this loop, so it was made ready twice
+		}
+		delete(n.orphans, e.Hash())
+		if err := n.validateParents(e); err != nil {
+			return false, err
+		}
+		n.insertReceivedEvent(e)
+		for _, child := range children[e.Hash()] {
+			if n.parentsKnown(child) {
+				ready = append(ready, child)
+			}
+		}
+	}
+
+	// Orphans of an earlier sync are checked by that sync
+	complete := true
+	for _, e := range receivedEvents {
+		if _, orphan := n.orphans[e.Hash()]; orphan && !n.parentsAvailable(e) {
+			complete = false
+		}
+	}
+	if len(n.orphans) > maxOrphanCount {
+		n.orphans = make(map[string]*Event)
+	}
+	return complete, nil
+}
+
+// Returns true if the event is an initial event of a member whose first events I pruned. A sender that lags behind
+// sends it again, since it can not tell that I pruned it. Inserting it would bring back the pruned events on top of it.
+func (n *Node) isPrunedInitialEvent(e *Event) bool {
+	return isInitial(e) && n.PrunedEventCount[e.Owner] > 0
+}
+
+// Calculates the state of a received event instead of trusting the sender, then inserts it to the hashgraph
+func (n *Node) insertReceivedEvent(e *Event) {
+	n.calculateRound(e)
+	n.insertEvent(e, true)
+}
+
+// Clears the state of an event whose parents are known, then calculates its round and whether it is a witness. Fame
+// and order are decided later.
+func (n *Node) calculateRound(e *Event) {
+	e.resetState()
+	if isInitial(e) {
+		e.Round = 1
+		e.IsWitness = true
+	} else {
+		n.DivideRounds(e)
+	}
+}
+
+// Checks that the self-parent of an event belongs to its owner and the other-parent belongs to another member,
+// the parents must be known
+func (n *Node) validateParents(e *Event) error {
+	if isInitial(e) {
+		return nil
+	}
+	if selfParent := n.Events[e.SelfParentHash]; selfParent.Owner != e.Owner {
+		return fmt.Errorf("event %s of %s has a self-parent of %s", e.Hash(), e.Owner, selfParent.Owner)
+	}
+	if otherParent := n.Events[e.OtherParentHash]; otherParent.Owner == e.Owner {
+		return fmt.Errorf("event %s of %s has an other-parent of its own", e.Hash(), e.Owner)
+	}
+	return nil
+}
+
+// Returns true if the event is an initial event or both of its parents are in the hashgraph
+func (n *Node) parentsKnown(e *Event) bool {
+	if isInitial(e) {
+		return true
+	}
+	_, okSelfParent := n.Events[e.SelfParentHash]
+	_, okOtherParent := n.Events[e.OtherParentHash]
+	return okSelfParent && okOtherParent
+}
+
+// Returns true if each parent of the event is either in the hashgraph or buffered as an orphan
+func (n *Node) parentsAvailable(e *Event) bool {
+	for _, parentHash := range []string{e.SelfParentHash, e.OtherParentHash} {
+		_, known := n.Events[parentHash]
+		_, orphan := n.orphans[parentHash]
+		if !known && !orphan {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,33 @@
+package hashgraph
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+)
+
+//MemberID : Identifies a member of the distributed ledger independently of its network address. It is the hex encoded
+// public key that the member signs its events with, so a member keeps its ID when it moves to another address.
+type MemberID string
+
+//NewMemberID : Returns the ID of the member that signs its events with the given public key
+func NewMemberID(publicKey ed25519.PublicKey) MemberID {
+	return MemberID(hex.EncodeToString(publicKey))
+}
+
+//PublicKey : Returns the public key of the member, or nil if the ID is not a hex encoded public key
+func (id MemberID) PublicKey() ed25519.PublicKey {
+	publicKey, err := hex.DecodeString(string(id))
+	if err != nil || len(publicKey) != ed25519.PublicKeySize {
+		return nil
+	}
+	return publicKey
+}
+
+// Returns the public key of a member of the ledger, ok is false if the ID does not belong to a member
+func (n *Node) publicKeyOf(id MemberID) (ed25519.PublicKey, bool) {
+	if _, ok := n.Hashgraph[id]; !ok {
+		return nil, false
+	}
+	publicKey := id.PublicKey()
+	return publicKey, publicKey != nil
+}
@@ -1,172 +1,340 @@
 package hashgraph
 
 import (
+    "bytes"
+    "crypto/ed25519"
+    "encoding/hex"
+    "errors"
     "fmt"
     "math"
-    "math/rand"
     "sort"
     "sync"
     "time"
-
-    uuid "github.com/satori/go.uuid"
 )
 
 const (
-    randomTransactionCount     = 2   // How many transactions to generate for each event
-    randomTransactionAmountMax = 500 // Maximum amount in a random transaction
-    randomTransactionAmountMin = 10  // Minimum amount in a random transaction
+    defaultCoinRoundFrequency = 10 // Every this many rounds of an election is a coin round
+    defaultRetentionRounds    = 10 // How many rounds of events to keep before the last round that reached consensus
+    defaultSnapshotInterval   = 3  // Every this many settled rounds a snapshot is taken, well within the retention window
 )
 
-//Node : A member of the distributed ledger system. Is identified by it's address.
+//Node : A member of the distributed ledger system. Is identified by it's member ID.
 type Node struct {
     sync.RWMutex
-    Address                       string                       // ip:port of the peer
-    Hashgraph                     map[string][]*Event          // local copy of hashgraph, map to peer address -> peer events
-    Events                        map[string]*Event            // events as a map of signature -> event
-    Witnesses                     map[string]map[uint32]*Event // map of peer addres -> (map of round -> witness)
-    FirstRoundOfFameUndecided     map[string]uint32            // the round of first witness that's fame is undecided for each peer
-    FirstEventOfNotConsensusIndex map[string]int               // the index of first non-consensus event
-    ConsensusEvents               []*Event                     // list of events with roundReceived and consensusTimestamp
-    TransactionBuffer             []Transaction                // slice of transactions stored until next gossip
-    seeDPMemory                   map[string]map[string]bool   // a map from p.Signature to q.Signature that yields whether it p sees q or not
-}
-
-//NewNode : Construct a new node for the distributed ledger
-func NewNode(initialHashgraph map[string][]*Event, address string) *Node {
+    ID                            MemberID                       // public key of the member, which identifies it regardless of its address
+    Hashgraph                     map[MemberID][]*Event          // local copy of hashgraph, map to member ID -> member events
+    Events                        map[string]*Event              // events as a map of hash -> event
+    Witnesses                     map[MemberID]map[uint32]*Event // map of member ID -> (map of round -> witness)
+    FirstRoundOfFameUndecided     map[MemberID]uint32            // the round of first witness that's fame is undecided for each peer
+    FirstEventOfNotConsensusIndex map[MemberID]int               // the index of first non-consensus event
+    ConsensusEvents               []*Event                       // list of events with roundReceived and consensusTimestamp, events of pruned rounds are removed
+    PrunedConsensusCount          int                            // number of events that reached consensus before the first one in ConsensusEvents, which are pruned or before the installed snapshot
+    CoinRoundFrequency            uint32                         // every c-th round of a fame election is a coin round, 0 disables coin rounds
+    RetentionRounds               uint32                         // events older than this many rounds before the last settled round are pruned
+    SnapshotInterval              uint32                         // a snapshot is taken every this many settled rounds, must be well below RetentionRounds for lagging members to continue from it
+    PrunedEventCount              map[MemberID]int               // map of member ID -> number of events of that member that are pruned from the hashgraph
+    Store                         EventStore                     // persists inserted events and consensus results, nil if the node is not persisted
+    pendingRecords                []StoreRecord                  // records of the current sync, appended to the store together at its end
+    storeErr                      error                          // first failure of the store, the node accepts and shares no events after it
+    Application                   Application                    // state machine that the transactions are delivered to in consensus order, nil if there is none
+    TransactionBuffer             []Transaction                  // slice of transactions stored until next gossip
+    Stakes                        map[MemberID]uint64            // map of member ID -> weight of that member in supermajority decisions
+    Forks                         map[MemberID][]ForkProof       // map of member ID -> proofs of the forks made by that member
+    seeDPMemory                   map[string]map[string]bool     // a map from p.Hash() to q.Hash() that yields whether q is an ancestor of p or not
+    selfChildren                  map[selfParentKey]*Event       // first known event of a member with a given self-parent, used to detect forks
+    votes                         map[string]map[string]bool     // a map from y.Hash() to x.Hash() that yields the vote of witness y about the fame of witness x
+    orphans                       map[string]*Event              // received events whose parents are not known yet, map of hash -> event
+    firstRetainedRound            uint32                         // events with smaller rounds are pruned
+    lastRoundReceived             uint32                         // last round that events are received in, rounds are received in increasing order
+    latestSnapshot                *Snapshot                      // snapshot taken at the last round boundary, or installed from a peer
+    certifiedSnapshot             *Snapshot                      // latest snapshot whose state is signed by a supermajority, which is given to lagging members
+    uncertifiedSnapshots          []*Snapshot                    // snapshots that wait for the signatures of a supermajority over their states, oldest first
+    stateSignatures               map[uint32][]StateSignature    // map of round -> signatures of the members over their states after the round, one per member
+    consensusDigests              map[uint32]string              // map of round -> digest of the consensus up to the round, for the rounds that are not pruned
+    needsSnapshot                 bool                           // set when peers sent events whose parents they pruned, or pruned the parents of my events
+    privateKey                    ed25519.PrivateKey             // key that this node signs its own events with
+    totalStake                    uint64                         // sum of the stakes of all members
+}
+
+//NewNode : Construct a new node for the distributed ledger. Members are the keys of the initial hashgraph, the ID of
+// the node is derived from its private key. If stakes is nil, every member has an equal stake.
+func NewNode(initialHashgraph map[MemberID][]*Event, privateKey ed25519.PrivateKey, stakes map[MemberID]uint64) *Node {
+    if stakes == nil {
+        stakes = make(map[MemberID]uint64, len(initialHashgraph))
+        for id := range initialHashgraph {
+            stakes[id] = 1
+        }
+    }
+    totalStake := uint64(0)
+    for _, stake := range stakes {
+        totalStake += stake
+    }
+
     return &Node{
-        Address:                       address,
+        ID:                            NewMemberID(privateKey.Public().(ed25519.PublicKey)),
         Hashgraph:                     initialHashgraph,
         Events:                        make(map[string]*Event),
-        Witnesses:                     make(map[string]map[uint32]*Event),
-        FirstRoundOfFameUndecided:     make(map[string]uint32),
-        FirstEventOfNotConsensusIndex: make(map[string]int),
+        Witnesses:                     make(map[MemberID]map[uint32]*Event),
+        FirstRoundOfFameUndecided:     make(map[MemberID]uint32),
+        FirstEventOfNotConsensusIndex: make(map[MemberID]int),
+        CoinRoundFrequency:            defaultCoinRoundFrequency,
+        RetentionRounds:               defaultRetentionRounds,
+        SnapshotInterval:              defaultSnapshotInterval,
+        PrunedEventCount:              make(map[MemberID]int),
+        Stakes:                        stakes,
+        Forks:                         make(map[MemberID][]ForkProof),
         seeDPMemory:                   make(map[string]map[string]bool),
+        selfChildren:                  make(map[selfParentKey]*Event),
+        votes:                         make(map[string]map[string]bool),
+        orphans:                       make(map[string]*Event),
+        stateSignatures:               make(map[uint32][]StateSignature),
+        consensusDigests:              map[uint32]string{0: ""},
+        privateKey:                    privateKey,
+        totalStake:                    totalStake,
     }
 }
 
-//Transaction : A statement of money transfer from a sender to a receiver.
-type Transaction struct {
-    SenderAddress   string  `json:"sender_address"`   // ip:port of sender
-    ReceiverAddress string  `json:"receiver_address"` // ip:port of receiver
-    Amount          float64 `json:"amount"`           // amount
-}
+//Transaction : An opaque payload of an event, which is only interpreted by the application on top of the hashgraph
+type Transaction []byte
 
 //SyncEventsDTO : Data Transfer Object for SyncAllEvents function
 type SyncEventsDTO struct {
-    SenderAddress string              // address of the node who made the call
-    MissingEvents map[string][]*Event // map of addresses to events of those addresses that are missing on the remotely called node
+    SenderID          MemberID              // ID of the node who made the call
+    MissingEvents     map[MemberID][]*Event // map of member IDs to events of those members that are missing on the remotely called node
+    StateSignatures   []StateSignature      // signatures of the sender over its state after the rounds that it committed last
+    LastRoundReceived uint32                // last round that the sender received events in, tells which of the two lags behind
 }
 
-//GetNumberOfMissingEvents : Node A calls Node B to learn which events B does not know and A knows.
-func (n *Node) GetNumberOfMissingEvents(numEventsAlreadyKnown map[string]int, numEventsToSend *map[string]int) error {
+//LatestEventsDTO : Data Transfer Object for GetLatestEvents function
+type LatestEventsDTO struct {
+    Tips map[MemberID][]string // map of member IDs to hashes of the latest known events of those members, more than one for a member that forked
+}
+
+//GetLatestEvents : Node A calls Node B to learn the latest events that B knows of each member. B knows these events and
+// all of their ancestors, so A can find the events that B does not know with MissingEvents.
+func (n *Node) GetLatestEvents(_ bool, latest *LatestEventsDTO) error {
     n.RWMutex.RLock()
-    for addr := range n.Hashgraph {
-        (*numEventsToSend)[addr] = numEventsAlreadyKnown[addr] - len(n.Hashgraph[addr])
+    defer n.RWMutex.RUnlock()
+
+    if n.storeErr != nil {
+        return n.storeErr // my latest event may not be persisted, I would fork after a restart
+    }
+    latest.Tips = make(map[MemberID][]string, len(n.Hashgraph))
+    for id, events := range n.Hashgraph {
+        hasSelfChild := make(map[string]bool, len(events))
+        for _, e := range events {
+            hasSelfChild[e.SelfParentHash] = true
+        }
+        for _, e := range events {
+            if !hasSelfChild[e.Hash()] {
+                latest.Tips[id] = append(latest.Tips[id], e.Hash())
+            }
+        }
     }
-    n.RWMutex.RUnlock()
     return nil
 }
 
-//SyncAllEvents : Node A first calls GetNumberOfMissingEvents on B, and then sends the missing events in this function
-func (n *Node) SyncAllEvents(events SyncEventsDTO, success *bool) error {
+//MissingEvents : Returns the events that I know and the node with the given latest events does not know, in the order
+// I inserted them. Ancestors of the latest events are known by that node, but I can not tell which ancestors of the
+// latest events that I do not know are known by that node, so some of the returned events may already be known.
+func (n *Node) MissingEvents(latest LatestEventsDTO) map[MemberID][]*Event {
+    n.RWMutex.RLock()
+    defer n.RWMutex.RUnlock()
+
+    // Walk down from the latest events through their parents, every event on the way is known by that node
+    known := make(map[string]bool)
+    var stack []*Event
+    for _, hashes := range latest.Tips {
+        for _, hash := range hashes {
+            if e, ok := n.Events[hash]; ok {
+                stack = append(stack, e)
+            }
+        }
+    }
+    for len(stack) > 0 {
+        e := stack[len(stack)-1]
+        stack = stack[:len(stack)-1]
+        if known[e.Hash()] {
+            continue
+        }
+        known[e.Hash()] = true
+        for _, parentHash := range []string{e.SelfParentHash, e.OtherParentHash} {
+            if parent, ok := n.Events[parentHash]; ok && !known[parentHash] {
+                stack = append(stack, parent) // parents that I pruned are not needed anymore
+            }
+        }
+    }
+
+    missingEvents := make(map[MemberID][]*Event, len(n.Hashgraph))
+    for id, events := range n.Hashgraph {
+        for _, e := range events {
+            if !known[e.Hash()] {
+                missingEvents[id] = append(missingEvents[id], e)
+            }
+        }
+    }
+    return missingEvents
+}
+
+//ErrSenderLagsBehind : Returned to a sender whose events have parents that I pruned, it needs a snapshot to catch up
+var ErrSenderLagsBehind = errors.New("sender lags behind, its events have pruned parents")
+
+//SyncAllEvents : Node A first calls GetLatestEvents on B, and then sends the missing events in this function. Returns an
+// error that wraps ErrStoreFailed if the store of B fails, B accepts no more events after it. If some events can not
+// be inserted since their parents are pruned, whichever of A and B received fewer rounds needs a snapshot: B reports
+// false success for itself and ErrSenderLagsBehind for A.
+func (n *Node) SyncAllEvents(events SyncEventsDTO, success *bool) (err error) {
     n.RWMutex.Lock()
-    otherPeerAddresses := make([]string, len(n.Hashgraph)-1)
-    for addr := range n.Hashgraph {
-        if addr != n.Address {
-            otherPeerAddresses = append(otherPeerAddresses, addr)
+    defer n.RWMutex.Unlock()
+
+    if n.storeErr != nil {
+        return n.storeErr
+    }
+    // Records of the sync are flushed to the disk once, before my peers learn my new event
+    defer func() {
+        if flushErr := n.flushRecords(); flushErr != nil {
+            *success = false
+            err = flushErr
         }
+    }()
 
+    if _, ok := n.Hashgraph[events.SenderID]; !ok || events.SenderID == n.ID {
+        return fmt.Errorf("sender %s is not another member", events.SenderID)
     }
-    transactions := n.GenerateTransactions(randomTransactionCount, randomTransactionAmountMax, randomTransactionAmountMin, otherPeerAddresses)
 
-    // Add the missing events to my local hashgraph
-    for addr := range events.MissingEvents {
-        for _, missingEvent := range events.MissingEvents[addr] {
-            _, ok := n.Events[missingEvent.Signature]
-            if !ok {
-                n.Hashgraph[addr] = append(n.Hashgraph[addr], missingEvent)
-                n.Events[missingEvent.Signature] = missingEvent
-                if missingEvent.IsWitness {
-                    n.Witnesses[missingEvent.Owner][missingEvent.Round] = missingEvent
-                }
+    // Reject the whole sync if any of the events was not signed by it's owner or has only one parent
+    var receivedEvents []*Event
+    for id := range events.MissingEvents {
+        for _, missingEvent := range events.MissingEvents[id] {
+            publicKey, ok := n.publicKeyOf(missingEvent.Owner)
+            if !ok || missingEvent.Owner != id || !missingEvent.VerifySignature(publicKey) {
+                return fmt.Errorf("event of %s has an invalid signature", missingEvent.Owner)
             }
+            if (missingEvent.SelfParentHash == "") != (missingEvent.OtherParentHash == "") {
+                return fmt.Errorf("event %s of %s has only one parent", missingEvent.Hash(), missingEvent.Owner)
+            }
+            receivedEvents = append(receivedEvents, missingEvent)
+        }
+    }
+    for _, signature := range events.StateSignatures {
+        if err := n.verifyStateSignature(signature); err != nil {
+            return err
         }
     }
+    n.addStateSignatures(events.StateSignatures)
+
+    // Add the missing events to my local hashgraph, parents first
+    complete, err := n.insertReceivedEvents(receivedEvents)
+    if err != nil {
+        return err
+    }
+    if !complete || len(n.Hashgraph[events.SenderID]) == 0 {
+        if events.LastRoundReceived <= n.lastRoundReceived {
+            return ErrSenderLagsBehind // I pruned the parents of the events of the sender
+        }
+        n.needsSnapshot = true // the sender pruned the events that I miss
+        *success = false
+        return nil
+    }
 
     // Store the transactions temporarily, and reset the global buffer
-    transactions = append(transactions, n.TransactionBuffer...)
+    transactions := n.TransactionBuffer
     n.TransactionBuffer = nil
 
-    // Create random signature
-    signatureUUID, err := uuid.NewV4()
-    handleError(err)
-    signature := signatureUUID.String()
-
     // Assign parents
-    newEventsSelfParent := n.Hashgraph[n.Address][len(n.Hashgraph[n.Address])-1]
-    newEventsOtherParent := n.Hashgraph[events.SenderAddress][len(n.Hashgraph[events.SenderAddress])-1]
+    newEventsSelfParent := n.Hashgraph[n.ID][len(n.Hashgraph[n.ID])-1]
+    newEventsOtherParent := n.Hashgraph[events.SenderID][len(n.Hashgraph[events.SenderID])-1]
 
     // Create event
     newEvent := Event{
-        Owner:              n.Address,
-        Signature:          signature,
-        SelfParentHash:     newEventsSelfParent.Signature,
-        OtherParentHash:    newEventsOtherParent.Signature,
-        Timestamp:          time.Now(),
-        Transactions:       transactions,
-        Round:              0,
-        IsWitness:          false,
-        IsFamous:           false,
-        IsFameDecided:      false,
-        RoundReceived:      0,
-        ConsensusTimestamp: time.Unix(0, 0),
+        Owner:           n.ID,
+        SelfParentHash:  newEventsSelfParent.Hash(),
+        OtherParentHash: newEventsOtherParent.Hash(),
+        Timestamp:       time.Now().UTC(), // without the monotonic clock reading, which is not part of the event on the wire
+        Transactions:    transactions,
+        eventState:      EventState{ConsensusTimestamp: time.Unix(0, 0)},
     }
+    newEvent.Sign(n.privateKey)
 
     // Find the round & witness of new event
     n.DivideRounds(&newEvent)
 
     // Update local arrays
-    if newEvent.IsWitness {
-        n.Witnesses[newEvent.Owner][newEvent.Round] = &newEvent
-    }
-    n.Events[newEvent.Signature] = &newEvent
-    n.Hashgraph[n.Address] = append(n.Hashgraph[n.Address], &newEvent)
+    n.insertEvent(&newEvent, true)
 
     // Decide fame on fame-undecided witnesses
     n.DecideFame()
     // Arrive to consensus on order of events
     n.FindOrder()
+    // Forget the events that are no longer needed for consensus
+    n.PruneEvents()
 
     *success = true
-    n.RWMutex.Unlock()
     return nil
 }
 
+//CreateInitialEvent : Creates the first event of this node, which has no parents and is the witness of round 1. Returns
+// the failure of the store of the node.
+func (n *Node) CreateInitialEvent() error {
+    initialEvent := Event{
+        Owner:           n.ID,
+        SelfParentHash:  "",
+        OtherParentHash: "",
+        Timestamp:       time.Now().UTC(),
+        Transactions:    nil,
+        eventState: EventState{
+            Round:              1,
+            IsWitness:          true, // true because the initial event is the first event of its round
+            ConsensusTimestamp: time.Unix(0, 0),
+        },
+    }
+    initialEvent.Sign(n.privateKey)
+    n.insertEvent(&initialEvent, true)
+    n.FirstRoundOfFameUndecided[initialEvent.Owner] = 1
+    return n.flushRecords()
+}
+
+// Adds the event to the local arrays, and to the store if persist is true
+func (n *Node) insertEvent(e *Event, persist bool) {
+    n.detectFork(e)
+    n.Events[e.Hash()] = e
+    n.Hashgraph[e.Owner] = append(n.Hashgraph[e.Owner], e)
+    if e.IsWitness {
+        if _, ok := n.Witnesses[e.Owner]; !ok {
+            n.Witnesses[e.Owner] = make(map[uint32]*Event)
+        }
+        n.Witnesses[e.Owner][e.Round] = e
+    }
+    if persist {
+        n.persist(StoreRecord{Kind: EventRecord, Event: e})
+    }
+}
+
 //DivideRounds : Calculates the round of a new event
 func (n *Node) DivideRounds(e *Event) {
     selfParent, okSelfParent := n.Events[e.SelfParentHash]
     otherParent, okOtherParent := n.Events[e.OtherParentHash]
     if !okSelfParent || !okOtherParent {
-        fmt.Printf("Parents were not ok: (self: %t, other: %t)\n", okSelfParent, okOtherParent)
-        return
+        panic(fmt.Sprintf("Parents were not ok: (self: %t, other: %t)", okSelfParent, okOtherParent)) // parents are always inserted first
     }
 
     // Round of this event is at least the round of it's parents
     r := max(selfParent.Round, otherParent.Round)
+    e.Round = r // ancestors of e have at most round r, so see() can rely on this before the final round is known
 
     // Get round r witnesses
     witnesses := n.findWitnessesOfARound(r)
 
-    // Count strongly seen witnesses in round r
-    stronglySeenWitnessCount := 0
+    // Sum the stake of the owners of strongly seen witnesses in round r
+    stronglySeenWitnessStake := uint64(0)
     for _, w := range witnesses {
         if n.stronglySee(e, w) {
-            stronglySeenWitnessCount++
+            stronglySeenWitnessStake += n.Stakes[w.Owner]
         }
     }
 
     // Check supermajority
-    if float64(stronglySeenWitnessCount) > (2.0 * float64(len(n.Hashgraph)) / 3.0) {
+    if n.isSuperMajority(stronglySeenWitnessStake) {
         e.Round = r + 1
     } else {
         e.Round = r
@@ -180,154 +348,290 @@ func (n *Node) DivideRounds(e *Event) {
 
 //DecideFame : Decides if a witness is famous or not
 func (n *Node) DecideFame() {
-    // Get the last witnesses that does not have a decided fame
-    var fameUndecidedWitnesses []*Event // this is "for each x" in the paper
-    for addr := range n.Hashgraph {
-        for round, witness := range n.Witnesses[addr] {
-            if round >= n.FirstRoundOfFameUndecided[addr] {
+    // Get the witnesses that do not have a decided fame, earlier rounds first
+    var fameUndecidedWitnesses eventPtrSliceByRound // this is "for each x" in the paper
+    for id := range n.Hashgraph {
+        for round, witness := range n.Witnesses[id] {
+            if round >= n.FirstRoundOfFameUndecided[id] && !witness.IsFameDecided {
                 fameUndecidedWitnesses = append(fameUndecidedWitnesses, witness)
             }
         }
     }
+    sort.Stable(fameUndecidedWitnesses)
 
     for _, e := range fameUndecidedWitnesses {
-        // Get all witnesses that have greater rounds
-        var witnessesWithGreaterRounds []*Event
-        for addr := range n.Hashgraph {
-            for round, witness := range n.Witnesses[addr] {
+        // Get all witnesses that have greater rounds, voters of a round need the votes of the round before them
+        var witnessesWithGreaterRounds eventPtrSliceByRound
+        for id := range n.Hashgraph {
+            for round, witness := range n.Witnesses[id] {
                 if round > e.Round {
                     witnessesWithGreaterRounds = append(witnessesWithGreaterRounds, witness)
                 }
             }
         }
+        sort.Stable(witnessesWithGreaterRounds)
 
         for _, w := range witnessesWithGreaterRounds {
+            d := w.Round - e.Round
+            if d == 1 {
+                // First round of the election, w votes for whether it can see e
+                n.setVote(w, e, n.see(w, e))
+                continue
+            }
+
             // Find witnesses of prior round
             witnessesOfRound := n.findWitnessesOfARound(w.Round - 1)
 
-            // Choose the strongly seen ones
-            var stronglySeenWitnessesOfRound []*Event
+            // Count the votes of the strongly seen ones, weighted by the stake of the voters
+            trueVotes := uint64(0)
+            falseVotes := uint64(0)
             for _, wr := range witnessesOfRound {
-                if n.stronglySee(w, wr) {
-                    stronglySeenWitnessesOfRound = append(stronglySeenWitnessesOfRound, wr)
+                if !n.stronglySee(w, wr) {
+                    continue
                 }
-            }
-
-            // Count votes
-            votes := make([]bool, len(stronglySeenWitnessesOfRound))
-            majority := 0
-            trueVotes := 0
-            falseVotes := 0
-            for i, voter := range stronglySeenWitnessesOfRound {
-                if n.see(voter, e) {
-                    votes[i] = true
-                    majority++
-                    trueVotes++
+                if n.votes[wr.Hash()][e.Hash()] {
+                    trueVotes += n.Stakes[wr.Owner]
                 } else {
-                    votes[i] = false
-                    majority--
-                    falseVotes++
+                    falseVotes += n.Stakes[wr.Owner]
                 }
             }
-            majorityVote := majority >= 0
-            superMajorityThreshold := 2.0 * float64(len(n.Hashgraph)) / 3.0
-            if (majorityVote && float64(trueVotes) > superMajorityThreshold) || (!majorityVote && float64(falseVotes) > superMajorityThreshold) {
-                e.IsFamous = majorityVote
-                e.IsFameDecided = true
-                n.FirstRoundOfFameUndecided[e.Owner] = e.Round + 1
-                break
+            majorityVote := trueVotes >= falseVotes
+            majorityVoteStake := falseVotes
+            if majorityVote {
+                majorityVoteStake = trueVotes
+            }
+            isSuperMajority := n.isSuperMajority(majorityVoteStake)
+
+            if n.CoinRoundFrequency == 0 || d%n.CoinRoundFrequency > 0 {
+                // Normal round, decide if there is a supermajority
+                n.setVote(w, e, majorityVote)
+                if isSuperMajority {
+                    e.IsFamous = majorityVote
+                    e.IsFameDecided = true
+                    n.updateFirstRoundOfFameUndecided(e.Owner)
+                    n.persist(StoreRecord{Kind: FameRecord, Hash: e.Hash(), IsFamous: e.IsFamous})
+                    break
+                }
+            } else if isSuperMajority {
+                // Coin round, follow the supermajority without deciding
+                n.setVote(w, e, majorityVote)
+            } else {
+                // Coin round, flip a coin that an adversary can not predict before w is signed
+                n.setVote(w, e, coinFlip(w))
             }
         }
     }
 }
 
-//FindOrder : Arrive at a consensus on the order of events
+// Records the vote of witness voter about the fame of witness candidate
+func (n *Node) setVote(voter *Event, candidate *Event, vote bool) {
+    votesOfVoter, ok := n.votes[voter.Hash()]
+    if !ok {
+        votesOfVoter = make(map[string]bool)
+        n.votes[voter.Hash()] = votesOfVoter
+    }
+    votesOfVoter[candidate.Hash()] = vote
+}
+
+// After a fame decision, move the first undecided round of the member to its earliest witness without a decided fame.
+// A member may not have a witness in every round, so if all of its witnesses are decided, it is the round after the last one.
+func (n *Node) updateFirstRoundOfFameUndecided(id MemberID) {
+    hasUndecidedWitness := false
+    firstUndecidedRound := uint32(0)
+    roundAfterDecided := n.FirstRoundOfFameUndecided[id]
+    for round, witness := range n.Witnesses[id] {
+        if witness.IsFameDecided {
+            roundAfterDecided = max(roundAfterDecided, round+1)
+        } else if !hasUndecidedWitness || round < firstUndecidedRound {
+            hasUndecidedWitness = true
+            firstUndecidedRound = round
+        }
+    }
+    if hasUndecidedWitness {
+        n.FirstRoundOfFameUndecided[id] = firstUndecidedRound
+    } else {
+        n.FirstRoundOfFameUndecided[id] = roundAfterDecided
+    }
+}
+
+// Pseudo-random coin of a coin round, the middle bit of the signature of the voter
+func coinFlip(voter *Event) bool {
+    signature, err := hex.DecodeString(voter.Signature)
+    if err != nil || len(signature) == 0 {
+        return false
+    }
+    middleBit := len(signature) * 8 / 2
+    return signature[middleBit/8]&(0x80>>(middleBit%8)) != 0
+}
+
+//FindOrder : Arrive at a consensus on the order of events. Rounds are received one by one in increasing order, a round
+// can be received once the fame of all of its witnesses is decided. Events that are seen by all famous witnesses of the
+// round are received in that round and appended to the consensus events in a deterministic order.
 func (n *Node) FindOrder() {
-    // Find events
-    var nonConsensusEvents []*Event
-    for addr := range n.Hashgraph {
-        nonConsensusEvents = append(nonConsensusEvents, n.Hashgraph[addr][n.FirstEventOfNotConsensusIndex[addr]:]...)
-    }
-    for _, e := range nonConsensusEvents {
-        // First & Third conditions: find a valid round number
-        r := n.FirstRoundOfFameUndecided[e.Owner] // owner is arbitrary
-        for _, round := range n.FirstRoundOfFameUndecided {
-            if round < r {
-                r = round
+    for {
+        r := n.lastRoundReceived + 1
+        witnesses := n.findWitnessesOfARound(r)
+        if len(witnesses) == 0 {
+            return
+        }
+        var famousWitnesses []*Event
+        for _, w := range witnesses {
+            if !w.IsFameDecided {
+                return
+            }
+            if w.IsFamous {
+                famousWitnesses = append(famousWitnesses, w)
             }
         }
-        witnesses := n.findWitnessesOfARound(r)
-        if len(witnesses) != 0 {
-            // Second condition: make sure x is seen by all famous witnesses
-            condMet := true
-            for _, w := range witnesses {
-                if w.IsFamous && !n.see(w, e) {
-                    condMet = false
-                    break
+        whitener := xorSignatures(famousWitnesses)
+
+        var receivedEvents eventPtrSlice
+        for id := range n.Hashgraph {
+            for _, e := range n.Hashgraph[id][n.FirstEventOfNotConsensusIndex[id]:] {
+                if e.RoundReceived != 0 || len(famousWitnesses) == 0 {
+                    continue
                 }
-            }
-            if condMet {
-                // Construct consensus set
+                // Make sure x is seen by all famous witnesses
+                condMet := true
+                for _, w := range famousWitnesses {
+                    if !n.see(w, e) {
+                        condMet = false
+                        break
+                    }
+                }
+                if !condMet {
+                    continue
+                }
+                // Construct consensus set, earliest self-ancestor of each famous witness that sees e
                 var s []*Event
-                for _, w := range witnesses {
+                for _, w := range famousWitnesses {
                     z := w
                     for !isInitial(z) {
                         if z.Round < e.Round {
                             // if z is lower than e, e can't be ancestor of z
                             break
                         }
-                        if n.see(z, e) && !n.see(n.Events[z.SelfParentHash], e) {
+                        selfParent, ok := n.Events[z.SelfParentHash] // older events may be pruned, they can not see e
+                        if n.see(z, e) && (!ok || !n.see(selfParent, e)) {
                             s = append(s, z)
                         }
-                        z = n.Events[z.SelfParentHash]
+                        if !ok {
+                            break
+                        }
+                        z = selfParent
                     }
-                }
-                if len(s) != 0 {
-                    e.RoundReceived = r
-                    // Take median
-                    timestamps := make(timeSlice, len(s))
-                    for i, se := range s {
-                        timestamps[i] = se.Timestamp
+                    if isInitial(z) && n.see(z, e) {
+                        s = append(s, z)
                     }
-
-                    sort.Stable(timestamps) // returns timestamps sorted in increasing order
-                    medianTimestamp := timestamps[int(math.Floor(float64(len(timestamps))/2.0))]
-                    e.ConsensusTimestamp = medianTimestamp
-                    e.Latency = time.Now().Sub(e.Timestamp) // Event's timestamp was set during it's creation
-                    n.ConsensusEvents = append(n.ConsensusEvents, e)
-                    n.FirstEventOfNotConsensusIndex[e.Owner]++ // !
                 }
+                e.RoundReceived = r
+                // Take median
+                timestamps := make(timeSlice, len(s))
+                for i, se := range s {
+                    timestamps[i] = se.Timestamp
+                }
+                sort.Stable(timestamps) // returns timestamps sorted in increasing order
+                e.ConsensusTimestamp = timestamps[int(math.Floor(float64(len(timestamps))/2.0))]
+                e.Latency = time.Now().Sub(e.Timestamp) // Event's timestamp was set during it's creation
+                e.whitenedSignature = whitenSignature(e, whitener)
+                receivedEvents = append(receivedEvents, e)
             }
         }
+
+        // Events of earlier rounds are already ordered, so only the events of this round need sorting
+        sort.Sort(receivedEvents)
+        for _, e := range receivedEvents {
+            n.ConsensusEvents = append(n.ConsensusEvents, e)
+            n.persist(StoreRecord{Kind: ConsensusRecord, Hash: e.Hash(), RoundReceived: e.RoundReceived, ConsensusTimestamp: e.ConsensusTimestamp})
+            n.deliver(e)
+        }
+        for id := range n.Hashgraph {
+            n.updateFirstEventOfNotConsensusIndex(id)
+        }
+        n.lastRoundReceived = r
+        n.persist(StoreRecord{Kind: RoundRecord, RoundReceived: r})
+        n.advanceConsensusDigest(r, receivedEvents)
+        n.commit(r)
+        n.signState(r)
+    }
+}
+
+// Moves the first event of not consensus index of the member past its events that reached consensus
+func (n *Node) updateFirstEventOfNotConsensusIndex(id MemberID) {
+    events := n.Hashgraph[id]
+    for n.FirstEventOfNotConsensusIndex[id] < len(events) && events[n.FirstEventOfNotConsensusIndex[id]].RoundReceived != 0 {
+        n.FirstEventOfNotConsensusIndex[id]++
+    }
+}
+
+// XOR of the signatures of the given witnesses, the result is independent of the order of witnesses
+func xorSignatures(witnesses []*Event) []byte {
+    var result []byte
+    for _, w := range witnesses {
+        signature, err := hex.DecodeString(w.Signature)
+        handleError(err)
+        if len(signature) > len(result) {
+            result = append(result, make([]byte, len(signature)-len(result))...)
+        }
+        for i := range signature {
+            result[i] ^= signature[i]
+        }
     }
-    // Bring all consensus events ordered
-    consensusSlice := make(eventPtrSlice, len(n.ConsensusEvents))
-    consensusSlice = n.ConsensusEvents
-    sort.Stable(consensusSlice)
-    n.ConsensusEvents = consensusSlice
+    return result
 }
 
-// If we can reach to target using downward edges only, we can see it. Downward in this case means that we reach through either parent. This function is used for voting
+// Signature of the event XORed with the whitener. Unlike the signature itself, a member can not choose a whitened
+// signature that puts its event first, since the whitener is only known after the event is created.
+func whitenSignature(e *Event, whitener []byte) []byte {
+    signature, err := hex.DecodeString(e.Signature)
+    handleError(err)
+    whitened := append([]byte(nil), signature...)
+    if len(whitener) > len(whitened) {
+        whitened = append(whitened, make([]byte, len(whitener)-len(whitened))...)
+    }
+    for i := range whitener {
+        whitened[i] ^= whitener[i]
+    }
+    return whitened
+}
+
+// If target is an ancestor of current, we can see it unless we can also see that the owner of target forked. This function is used for voting
 func (n *Node) see(current *Event, target *Event) bool {
-    dpMap, ok := n.seeDPMemory[current.Signature]
+    if !n.ancestor(current, target) {
+        return false
+    }
+    if _, isForker := n.Forks[target.Owner]; isForker && n.seesForkBy(current, target.Owner) {
+        return false
+    }
+    return true
+}
+
+// If we can reach to target using downward edges only, target is an ancestor. Downward in this case means that we reach through either parent.
+func (n *Node) ancestor(current *Event, target *Event) bool {
+    dpMap, ok := n.seeDPMemory[current.Hash()]
 
     if !ok {
         dpMap = make(map[string]bool)
-        n.seeDPMemory[current.Signature] = dpMap
+        n.seeDPMemory[current.Hash()] = dpMap
     }
 
-    cachedResult, ok := dpMap[target.Signature]
+    cachedResult, ok := dpMap[target.Hash()]
 
     if ok {
         return cachedResult
     }
 
-    if current.Signature == target.Signature || (current.Round > target.Round && current.Owner == target.Owner) {
-        dpMap[target.Signature] = true
+    if current.Hash() == target.Hash() {
+        dpMap[target.Hash()] = true
+        return true
+    }
+    // Timeline of a member is a chain unless it forked, so a later event of the same member descends from target
+    if _, isForker := n.Forks[current.Owner]; !isForker && current.Round > target.Round && current.Owner == target.Owner {
+        dpMap[target.Hash()] = true
         return true
     }
-    if (current.Round < target.Round) || (current.IsWitness && current.Round == target.Round) || isInitial(current) {
-        dpMap[target.Signature] = false
+    if current.Round < target.Round || isInitial(current) {
+        dpMap[target.Hash()] = false
         return false
     }
     /* SPONGE >>>
@@ -339,27 +643,36 @@ func (n *Node) see(current *Event, target *Event) bool {
        }
        SPONGE <<< */
 
-    result := n.see(n.Events[current.SelfParentHash], target) || n.see(n.Events[current.OtherParentHash], target)
-    dpMap[target.Signature] = result
+    // Parents may be pruned, but then they are older than any target we look for
+    selfParent, okSelfParent := n.Events[current.SelfParentHash]
+    otherParent, okOtherParent := n.Events[current.OtherParentHash]
+    result := (okSelfParent && n.ancestor(selfParent, target)) || (okOtherParent && n.ancestor(otherParent, target))
+    dpMap[target.Hash()] = result
     return result
 }
 
-// If we see the target, and we go through 2n/3 different nodes as we do that, we say we strongly see that target. This function is used for choosing the famous witness
+// If we see the target, and we go through nodes with more than 2/3 of the stake as we do that, we say we strongly see that target. This function is used for choosing the famous witness
 func (n *Node) stronglySee(current *Event, target *Event) bool {
     latestAncestors := n.getLatestAncestorFromAllNodes(current, target.Round)
-    count := 0
+    stake := uint64(0)
     for _, latestAncestor := range latestAncestors {
-        if n.see(latestAncestor, target) {
-            count++
+        // Events of a member that we know forked do not count once we see the fork
+        if n.see(current, latestAncestor) && n.see(latestAncestor, target) {
+            stake += n.Stakes[latestAncestor.Owner]
         }
     }
 
-    return float64(count) > (2.0 * float64(len(n.Hashgraph)) / 3.0)
+    return n.isSuperMajority(stake)
+}
+
+// Returns true if the given stake is more than 2/3 of the total stake of all members
+func (n *Node) isSuperMajority(stake uint64) bool {
+    return 3*stake > 2*n.totalStake
 }
 
 // Do breadth first search to find the latest ancestor that event e can see on every node
-func (n *Node) getLatestAncestorFromAllNodes(e *Event, minRound uint32) map[string]*Event {
-    latestAncestors := make(map[string]*Event, len(n.Hashgraph))
+func (n *Node) getLatestAncestorFromAllNodes(e *Event, minRound uint32) map[MemberID]*Event {
+    latestAncestors := make(map[MemberID]*Event, len(n.Hashgraph))
     if !isInitial(e) {
         // Queue for BFS
         var queue []*Event
@@ -372,7 +685,7 @@ func (n *Node) getLatestAncestorFromAllNodes(e *Event, minRound uint32) map[stri
             queue[0] = nil
             queue = queue[1:]
 
-            // Check if we have assigned an ancestor for this address yet
+            // Check if we have assigned an ancestor for this member yet
             currentAncestorFromOwner, ok := latestAncestors[currentEvent.Owner]
 
             if !ok {
@@ -406,12 +719,12 @@ func (n *Node) getLatestAncestorFromAllNodes(e *Event, minRound uint32) map[stri
 
 // Find witnesses of round r, which is the first event with round r in every node
 // note that it is possible that a node does not have a witness on a round r while the others do
-func (n *Node) findWitnessesOfARound(r uint32) map[string]*Event {
-    witnesses := make(map[string]*Event, len(n.Hashgraph))
-    for addr := range n.Hashgraph {
-        w, ok := n.Witnesses[addr][r]
+func (n *Node) findWitnessesOfARound(r uint32) map[MemberID]*Event {
+    witnesses := make(map[MemberID]*Event, len(n.Hashgraph))
+    for id := range n.Hashgraph {
+        w, ok := n.Witnesses[id][r]
         if ok {
-            witnesses[addr] = w
+            witnesses[id] = w
         }
     }
     return witnesses
@@ -430,29 +743,6 @@ func isInitial(e *Event) bool {
     return e.SelfParentHash == "" || e.OtherParentHash == ""
 }
 
-//GenerateTransactions : Generates an arbitrary amount of random transactions
-func (n *Node) GenerateTransactions(count int, max float64, min float64, peerAddress []string) []Transaction {
-    // Prepare transactions
-    transactions := make([]Transaction, count)
-    for i := 0; i < count; i++ {
-        randomPeerAddress := peerAddress[rand.Intn(len(peerAddress))]
-
-        for randomPeerAddress == "" {
-            randomPeerAddress = peerAddress[rand.Intn(len(peerAddress))]
-        }
-
-        randomAmount := min + rand.Float64()*(max-min)
-        transactions[i] = Transaction{
-            SenderAddress:   n.Address,
-            ReceiverAddress: randomPeerAddress,
-            Amount:          randomAmount,
-        }
-    }
-
-    return transactions
-
-}
-
 /** timeSlice interface for sorting **/
 type timeSlice []time.Time
 
@@ -475,9 +765,12 @@ func (p eventPtrSlice) Len() int {
 func (p eventPtrSlice) Less(i, j int) bool {
     if p[i].RoundReceived == p[j].RoundReceived {
         // round recieved may be same, break ties with timestamp
-        if p[i].ConsensusTimestamp == p[j].ConsensusTimestamp {
-            // timestamp may be same (though very unlikely)
-            return true
+        if p[i].ConsensusTimestamp.Equal(p[j].ConsensusTimestamp) {
+            // timestamp may be same (though very unlikely), break ties with whitened signature
+            if c := bytes.Compare(p[i].whitenedSignature, p[j].whitenedSignature); c != 0 {
+                return c < 0
+            }
+            return p[i].Hash() < p[j].Hash()
         }
         return p[i].ConsensusTimestamp.Before(p[j].ConsensusTimestamp)
     }
@@ -487,6 +780,19 @@ func (p eventPtrSlice) Swap(i, j int) {
     p[i], p[j] = p[j], p[i]
 }
 
+/** eventSlice interface for sorting by round **/
+type eventPtrSliceByRound []*Event
+
+func (p eventPtrSliceByRound) Len() int {
+    return len(p)
+}
+func (p eventPtrSliceByRound) Less(i, j int) bool {
+    return p[i].Round < p[j].Round
+}
+func (p eventPtrSliceByRound) Swap(i, j int) {
+    p[i], p[j] = p[j], p[i]
+}
+
 func handleError(e error) {
     if e != nil {
         panic(e)
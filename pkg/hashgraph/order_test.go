@@ -0,0 +1,53 @@
+package hashgraph
+
+import (
+	"math/rand"
+	"testing"
+)
+
+// Every member must arrive at the same consensus order whatever order it learns the events in
+func TestConsensusOrderIsIndependentOfInsertionOrder(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	d := newTestDAG(t, 4, defaultCoinRoundFrequency)
+	for i := 0; i < 300; i++ {
+		creator := random.Intn(4)
+		otherParent := (creator + 1 + random.Intn(3)) % 4
+		d.add(t, creator, otherParent)
+	}
+	expected := d.node.ConsensusEvents
+	if len(expected) == 0 {
+		t.Fatal("no event reached consensus")
+	}
+
+	orders := map[string][]*Event{"creation": d.events}
+	reversed := make([]*Event, len(d.events))
+	for i, e := range d.events {
+		reversed[len(d.events)-1-i] = e
+	}
+	orders["reversed"] = reversed
+	shuffled := append([]*Event(nil), d.events...)
+	random.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+	orders["shuffled"] = shuffled
+
+	for name, events := range orders {
+		n := d.newNode(defaultCoinRoundFrequency)
+		for len(events) > 0 {
+			batch := 1 + random.Intn(20)
+			if batch > len(events) {
+				batch = len(events)
+			}
+			receiveEvents(t, n, events[:batch])
+			events = events[batch:]
+		}
+		if len(n.ConsensusEvents) != len(expected) {
+			t.Fatalf("%s order: %d events reached consensus, expected %d", name, len(n.ConsensusEvents), len(expected))
+		}
+		for i, e := range n.ConsensusEvents {
+			if e.Hash() != expected[i].Hash() || e.RoundReceived != expected[i].RoundReceived ||
+				!e.ConsensusTimestamp.Equal(expected[i].ConsensusTimestamp) {
+				t.Fatalf("%s order: consensus event %d is %s of round %d, expected %s of round %d", name, i,
+					e.Hash(), e.RoundReceived, expected[i].Hash(), expected[i].RoundReceived)
+			}
+		}
+	}
+}
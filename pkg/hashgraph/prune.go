@@ -0,0 +1,122 @@
+package hashgraph
+
+//PruneEvents : Discards the events, consensus events, witnesses and cached results that are older than RetentionRounds rounds before the
+// last settled round. A round is settled once the fame of all witnesses up to that round is decided and all events up
+// to that round reached consensus, so pruned events are never needed to order or to calculate the round of new events.
+func (n *Node) PruneEvents() {
+	settledRound := n.lastSettledRound()
+	if settledRound <= n.RetentionRounds {
+		return
+	}
+	firstRetainedRound := settledRound - n.RetentionRounds
+	if firstRetainedRound <= n.firstRetainedRound {
+		return
+	}
+	n.firstRetainedRound = firstRetainedRound
+
+	pruned := make(map[string]bool)
+	for id := range n.Hashgraph {
+		// Events of a member are ordered by round received, only its events that reached consensus can be pruned. An event
+		// of a member that joined late may have an old round, but other members refer to it until it is received.
+		// Latest event of a member is always kept, as it will be the self-parent of its next event.
+		events := n.Hashgraph[id]
+		prunedCount := 0
+		for prunedCount < n.FirstEventOfNotConsensusIndex[id] && prunedCount < len(events)-1 && events[prunedCount].RoundReceived < firstRetainedRound {
+			pruned[events[prunedCount].Hash()] = true
+			delete(n.Events, events[prunedCount].Hash())
+			prunedCount++
+		}
+		if prunedCount == 0 {
+			continue
+		}
+		n.Hashgraph[id] = append([]*Event(nil), events[prunedCount:]...)
+		n.FirstEventOfNotConsensusIndex[id] -= prunedCount
+		n.PrunedEventCount[id] += prunedCount
+
+		for round := range n.Witnesses[id] {
+			if round < firstRetainedRound {
+				delete(n.Witnesses[id], round)
+			}
+		}
+	}
+
+	// Events that reached consensus in the pruned rounds are already delivered, only their count is kept
+	prunedConsensusCount := 0
+	for prunedConsensusCount < len(n.ConsensusEvents) && n.ConsensusEvents[prunedConsensusCount].RoundReceived < firstRetainedRound {
+		prunedConsensusCount++
+	}
+	n.ConsensusEvents = append([]*Event(nil), n.ConsensusEvents[prunedConsensusCount:]...)
+	n.PrunedConsensusCount += prunedConsensusCount
+
+	// Forget the cached results that are about pruned events
+	for hash, dpMap := range n.seeDPMemory {
+		if pruned[hash] {
+			delete(n.seeDPMemory, hash)
+			continue
+		}
+		for targetHash := range dpMap {
+			if pruned[targetHash] {
+				delete(dpMap, targetHash)
+			}
+		}
+	}
+	for hash, votesOfVoter := range n.votes {
+		if pruned[hash] {
+			delete(n.votes, hash)
+			continue
+		}
+		for candidateHash := range votesOfVoter {
+			if pruned[candidateHash] {
+				delete(votesOfVoter, candidateHash)
+			}
+		}
+	}
+	for key, e := range n.selfChildren {
+		if pruned[e.Hash()] {
+			delete(n.selfChildren, key)
+		}
+	}
+	for round := range n.stateSignatures {
+		if round < firstRetainedRound {
+			delete(n.stateSignatures, round)
+		}
+	}
+	for round := range n.consensusDigests {
+		if round+1 < firstRetainedRound {
+			delete(n.consensusDigests, round) // snapshots start from the digest before the first retained round
+		}
+	}
+
+	// Lagging members can continue from the events that are left. A snapshot encodes, signs and writes every retained
+	// event to the disk under the lock of the node, so it is only taken every SnapshotInterval settled rounds.
+	if n.latestSnapshot == nil || settledRound >= n.latestSnapshot.Round+n.SnapshotInterval {
+		n.takeSnapshot(settledRound)
+	}
+}
+
+// Returns the latest round R such that every witness up to round R has a decided fame and every event up to round R
+// reached consensus
+func (n *Node) lastSettledRound() uint32 {
+	settledRound := uint32(0)
+	for id := range n.Witnesses {
+		for round := range n.Witnesses[id] {
+			settledRound = max(settledRound, round)
+		}
+	}
+
+	for id := range n.Hashgraph {
+		for round, witness := range n.Witnesses[id] {
+			if !witness.IsFameDecided && round-1 < settledRound {
+				settledRound = round - 1
+			}
+		}
+		firstNonConsensusIndex := n.FirstEventOfNotConsensusIndex[id]
+		if firstNonConsensusIndex < len(n.Hashgraph[id]) {
+			firstNonConsensusEvent := n.Hashgraph[id][firstNonConsensusIndex]
+			if firstNonConsensusEvent.Round-1 < settledRound {
+				settledRound = firstNonConsensusEvent.Round - 1
+			}
+		}
+	}
+	return settledRound
+}
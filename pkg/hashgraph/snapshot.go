@@ -0,0 +1,448 @@
+package hashgraph
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	sentStateSignatureRounds = 3 // my signatures over my state after this many rounds that I committed last are sent with my events
+)
+
+//Snapshot : State of consensus at a round boundary, signed by the member that took it. A member that lags behind can
+// install a snapshot instead of receiving every event since the beginning, then continue with the events after it.
+// Only the state and the consensus that members with more than 2/3 of the stake signed after the same round are
+// installed, so a member can not make others install a state or a consensus that it made up.
+type Snapshot struct {
+	Round               uint32           `json:"round"`                      // Every event up to this round reached consensus and every witness up to this round has a decided fame
+	Owner               MemberID         `json:"owner"`                      // ID of the member that took the snapshot
+	State               []byte           `json:"state"`                      // State of the application after the events that reached consensus in this snapshot
+	ConsensusEventCount int              `json:"consensus_event_count"`      // Number of events that reached consensus before the snapshot was taken
+	LastRoundReceived   uint32           `json:"last_round_received"`        // Last round that events were received in before the snapshot was taken
+	Events              []SnapshotEvent  `json:"events"`                     // Events that are not pruned with their states, in the order they were inserted
+	PrunedEventCount    map[MemberID]int `json:"pruned_event_count"`         // Number of events of each member that are not in the snapshot
+	ConsensusDigest     string           `json:"consensus_digest"`           // Digest of the consensus up to the last round received, which the state signatures are over too
+	DigestBaseRound     uint32           `json:"digest_base_round"`          // Round that the consensus of the events of the snapshot is chained to
+	DigestBase          string           `json:"digest_base"`                // Digest of the consensus up to the base round
+	Signature           string           `json:"signature"`                  // Signature of the owner over the rest of the snapshot
+	StateSignatures     []StateSignature `json:"state_signatures,omitempty"` // Signatures of a supermajority over the state and the consensus after the last round received, not signed by the owner
+}
+
+//SnapshotEvent : An event in a snapshot along with its state. The state of an event that reached consensus can not be
+// calculated again without its pruned ancestors, so it is installed only if the consensus digest certifies it.
+type SnapshotEvent struct {
+	Event Event      `json:"event"`
+	State EventState `json:"state"`
+}
+
+//StateSignature : Signature of a member over the state of the application after it committed a round. Every member
+// arrives at the same state after a round, so the signatures of members with more than 2/3 of the stake certify it.
+type StateSignature struct {
+	Round               uint32   `json:"round"`                 // Round that the state is committed after
+	ConsensusEventCount int      `json:"consensus_event_count"` // Number of events that reached consensus up to the round
+	StateHash           string   `json:"state_hash"`            // Hex encoded SHA-256 hash of the state
+	ConsensusDigest     string   `json:"consensus_digest"`      // Digest of the famous witnesses and the received events of the rounds up to the round
+	Signer              MemberID `json:"signer"`                // ID of the member that signed the state
+	Signature           string   `json:"signature"`             // Signature of the signer over the rest
+}
+
+//ErrNoSnapshot : Returned when a node is asked for a snapshot before a supermajority signed the state of one
+var ErrNoSnapshot = errors.New("no snapshot is certified yet")
+
+//GetLatestSnapshot : A lagging node calls this on a peer to download its latest snapshot whose state is signed by a
+// supermajority
+func (n *Node) GetLatestSnapshot(_ bool, snapshot *Snapshot) error {
+	n.RWMutex.RLock()
+	defer n.RWMutex.RUnlock()
+
+	if n.certifiedSnapshot == nil {
+		return ErrNoSnapshot
+	}
+	*snapshot = *n.certifiedSnapshot
+	return nil
+}
+
+//StateSignatures : Returns my signatures over my state after the last rounds that I committed, which I send to my
+// peers with my events so that they can certify their snapshots
+func (n *Node) StateSignatures() []StateSignature {
+	n.RWMutex.RLock()
+	defer n.RWMutex.RUnlock()
+
+	var signatures []StateSignature
+	for round := n.lastRoundReceived; round > 0 && round+sentStateSignatureRounds > n.lastRoundReceived; round-- {
+		for _, signature := range n.stateSignatures[round] {
+			if signature.Signer == n.ID {
+				signatures = append(signatures, signature)
+			}
+		}
+	}
+	return signatures
+}
+
+//NeedsSnapshot : Returns true if the node received events whose parents were pruned by the sender, or a peer pruned
+// the parents of its events, so it can only catch up by installing a snapshot
+func (n *Node) NeedsSnapshot() bool {
+	n.RWMutex.RLock()
+	defer n.RWMutex.RUnlock()
+
+	return n.needsSnapshot
+}
+
+//RequestSnapshot : Marks that the node lags behind a peer that pruned the parents of its events, so it installs a
+// snapshot before it syncs again
+func (n *Node) RequestSnapshot() {
+	n.RWMutex.Lock()
+	defer n.RWMutex.Unlock()
+
+	n.needsSnapshot = true
+}
+
+//LastRoundReceived : Returns the last round that the node received events in, which it sends with its events
+func (n *Node) LastRoundReceived() uint32 {
+	n.RWMutex.RLock()
+	defer n.RWMutex.RUnlock()
+
+	return n.lastRoundReceived
+}
+
+//InstallSnapshot : Verifies the snapshot and the signatures of a supermajority over its state, then replaces the
+// hashgraph of the node with the events of the snapshot. Snapshots that are not ahead of the node are ignored. My
+// events that the snapshot does not include are inserted again on top of it while their parents are known, the
+// transactions of the rest are put back in my buffer for my next event.
+func (n *Node) InstallSnapshot(snapshot *Snapshot) error {
+	n.RWMutex.Lock()
+	defer n.RWMutex.Unlock()
+
+	if n.storeErr != nil {
+		return n.storeErr
+	}
+	if snapshot.Round <= n.lastSettledRound() {
+		n.needsSnapshot = false
+		return nil
+	}
+	if err := n.verifySnapshot(snapshot); err != nil {
+		return err
+	}
+	ownEvents := n.Hashgraph[n.ID]
+	if err := n.installSnapshot(snapshot); err != nil {
+		return err
+	}
+	n.certifiedSnapshot = snapshot
+	if err := n.checkpoint(snapshot); err != nil {
+		return err
+	}
+	n.rebuildTail(n.unknownEventsAfter(ownEvents))
+	return n.flushRecords()
+}
+
+// Inserts my events that the installed snapshot does not include, oldest first, as long as their parents are in the
+// snapshot. An event whose other-parent was pruned by my peers can never be inserted by them, so it is dropped with my
+// later events, and their transactions go back to the front of my buffer. My next event takes my last inserted event as
+// its self-parent and carries those transactions. It forks from the dropped event, which only a peer that did not
+// prune its parents could have inserted.
+func (n *Node) rebuildTail(tail []*Event) {
+	for i, e := range tail {
+		if !n.parentsKnown(e) || n.validateParents(e) != nil {
+			var transactions []Transaction
+			for _, dropped := range tail[i:] {
+				transactions = append(transactions, dropped.Transactions...)
+			}
+			n.TransactionBuffer = append(transactions, n.TransactionBuffer...)
+			return
+		}
+		n.insertReceivedEvent(e)
+	}
+}
+
+// Takes a snapshot of the events that are not pruned, the round must be settled. The snapshot is a checkpoint of the
+// store of the node, so that recovery starts from it instead of the beginning. Every retained event is encoded, signed
+// and written to the disk with the node locked, which is why snapshots are taken only every SnapshotInterval rounds.
+func (n *Node) takeSnapshot(round uint32) {
+	snapshot := &Snapshot{
+		Round:               round,
+		Owner:               n.ID,
+		ConsensusEventCount: n.PrunedConsensusCount + len(n.ConsensusEvents),
+		LastRoundReceived:   n.lastRoundReceived,
+		PrunedEventCount:    make(map[MemberID]int, len(n.PrunedEventCount)),
+		ConsensusDigest:     n.consensusDigests[n.lastRoundReceived],
+		DigestBaseRound:     n.consensusDigestBaseRound(),
+	}
+	snapshot.DigestBase = n.consensusDigests[snapshot.DigestBaseRound]
+	if n.Application != nil {
+		snapshot.State = n.Application.SnapshotState()
+	}
+	for id := range n.Hashgraph {
+		for _, e := range n.Hashgraph[id] {
+			// Copy, the state of the event may change later. Times are in UTC so that the signed encoding of the snapshot
+			// is the same after a wire format that does not keep time zones.
+			snapshotEvent := SnapshotEvent{Event: *e, State: e.State()}
+			snapshotEvent.Event.Timestamp = e.Timestamp.UTC()
+			snapshotEvent.State.ConsensusTimestamp = e.ConsensusTimestamp.UTC()
+			snapshot.Events = append(snapshot.Events, snapshotEvent)
+		}
+		snapshot.PrunedEventCount[id] = n.PrunedEventCount[id]
+	}
+
+	signature := ed25519.Sign(n.privateKey, snapshot.signedBytes())
+	snapshot.Signature = hex.EncodeToString(signature)
+	n.latestSnapshot = snapshot
+	if n.checkpoint(snapshot) != nil {
+		return // the sync reports the failure of the store
+	}
+
+	// Lagging members are only given the snapshot once a supermajority signed the same state
+	n.uncertifiedSnapshots = append(n.uncertifiedSnapshots, snapshot)
+	n.certifySnapshots()
+}
+
+// Signs my state after the round that I just committed
+func (n *Node) signState(round uint32) {
+	var state []byte
+	if n.Application != nil {
+		state = n.Application.SnapshotState()
+	}
+	signature := StateSignature{
+		Round:               round,
+		ConsensusEventCount: n.PrunedConsensusCount + len(n.ConsensusEvents),
+		StateHash:           hashState(state),
+		ConsensusDigest:     n.consensusDigests[round],
+		Signer:              n.ID,
+	}
+	signature.Signature = hex.EncodeToString(ed25519.Sign(n.privateKey, signature.signedBytes()))
+	n.addStateSignatures([]StateSignature{signature})
+}
+
+// Records the verified signatures over states, then certifies the snapshots whose states a supermajority signed
+func (n *Node) addStateSignatures(signatures []StateSignature) {
+	for _, signature := range signatures {
+		if signature.Round < n.firstRetainedRound || n.hasStateSignature(signature.Round, signature.Signer) {
+			continue // older than any snapshot that I may certify, or already known
+		}
+		n.stateSignatures[signature.Round] = append(n.stateSignatures[signature.Round], signature)
+	}
+	n.certifySnapshots()
+}
+
+// Returns true if I know the signature of the member over its state after the round
+func (n *Node) hasStateSignature(round uint32, signer MemberID) bool {
+	for _, signature := range n.stateSignatures[round] {
+		if signature.Signer == signer {
+			return true
+		}
+	}
+	return false
+}
+
+// Certifies the latest snapshot whose state a supermajority signed, older snapshots are no longer needed. Snapshots of
+// rounds that were committed more than RetentionRounds rounds ago are dropped without a certificate.
+func (n *Node) certifySnapshots() {
+	for i := len(n.uncertifiedSnapshots) - 1; i >= 0; i-- {
+		snapshot := n.uncertifiedSnapshots[i]
+		var signatures []StateSignature
+		stake := uint64(0)
+		for _, signature := range n.stateSignatures[snapshot.LastRoundReceived] {
+			if signature.certifies(snapshot) {
+				signatures = append(signatures, signature)
+				stake += n.Stakes[signature.Signer]
+			}
+		}
+		if n.isSuperMajority(stake) {
+			certified := *snapshot
+			certified.StateSignatures = signatures
+			n.certifiedSnapshot = &certified
+			n.uncertifiedSnapshots = append([]*Snapshot(nil), n.uncertifiedSnapshots[i+1:]...)
+			break
+		}
+	}
+	for len(n.uncertifiedSnapshots) > 0 && n.uncertifiedSnapshots[0].LastRoundReceived+n.RetentionRounds < n.lastRoundReceived {
+		n.uncertifiedSnapshots = n.uncertifiedSnapshots[1:]
+	}
+}
+
+// Checks that the signature over a state is signed by its signer, who is a member
+func (n *Node) verifyStateSignature(signature StateSignature) error {
+	publicKey, ok := n.publicKeyOf(signature.Signer)
+	decoded, err := hex.DecodeString(signature.Signature)
+	if !ok || err != nil || !ed25519.Verify(publicKey, signature.signedBytes(), decoded) {
+		return fmt.Errorf("state signature of %s for round %d is invalid", signature.Signer, signature.Round)
+	}
+	return nil
+}
+
+// Checks that the snapshot, its state and all of its events are signed. The snapshot is signed by its owner, its
+// events by their owners, and its state and consensus after the last round received by members with more than 2/3 of
+// the stake. The consensus digest is calculated again from the states of the events, so that their states are
+// certified too.
+func (n *Node) verifySnapshot(snapshot *Snapshot) error {
+	publicKey, ok := n.publicKeyOf(snapshot.Owner)
+	signature, err := hex.DecodeString(snapshot.Signature)
+	if !ok || err != nil || !ed25519.Verify(publicKey, snapshot.signedBytes(), signature) {
+		return fmt.Errorf("snapshot of %s has an invalid signature", snapshot.Owner)
+	}
+	signers := make(map[MemberID]bool)
+	stake := uint64(0)
+	for _, stateSignature := range snapshot.StateSignatures {
+		if err := n.verifyStateSignature(stateSignature); err != nil {
+			return err
+		}
+		if stateSignature.certifies(snapshot) && !signers[stateSignature.Signer] {
+			signers[stateSignature.Signer] = true
+			stake += n.Stakes[stateSignature.Signer]
+		}
+	}
+	if !n.isSuperMajority(stake) {
+		return fmt.Errorf("state of the snapshot of %s is not signed by a supermajority", snapshot.Owner)
+	}
+	if snapshot.DigestBaseRound > snapshot.LastRoundReceived || snapshot.replayConsensusDigest() != snapshot.ConsensusDigest {
+		return fmt.Errorf("consensus of the snapshot of %s does not match its certified digest", snapshot.Owner)
+	}
+	for i := range snapshot.Events {
+		e := &snapshot.Events[i].Event
+		publicKey, ok := n.publicKeyOf(e.Owner)
+		if !ok || !e.VerifySignature(publicKey) {
+			return fmt.Errorf("event of %s in snapshot has an invalid signature", e.Owner)
+		}
+	}
+	return nil
+}
+
+// Replaces the hashgraph of the node with the events of the snapshot. Events that reached consensus take their states
+// from the snapshot, which the consensus digest certifies for the rounds after its base round. An event that was
+// received in an earlier round is only kept as the latest event of its member, so it gets no later round than the one
+// it was received in. Rounds of the other events are calculated again on top of them, and the fame of their witnesses
+// after the last round received is decided again. Events whose parents are pruned are dropped, like received ones.
+func (n *Node) installSnapshot(snapshot *Snapshot) error {
+	if n.Application != nil {
+		if err := n.Application.RestoreState(snapshot.State); err != nil {
+			return err
+		}
+	}
+
+	for id := range n.Hashgraph {
+		n.Hashgraph[id] = nil
+		n.Witnesses[id] = make(map[uint32]*Event)
+		n.FirstEventOfNotConsensusIndex[id] = 0
+		n.PrunedEventCount[id] = snapshot.PrunedEventCount[id]
+		n.FirstRoundOfFameUndecided[id] = snapshot.LastRoundReceived + 1
+	}
+	n.Events = make(map[string]*Event)
+	n.ConsensusEvents = nil
+	n.PrunedConsensusCount = snapshot.ConsensusEventCount
+	n.seeDPMemory = make(map[string]map[string]bool)
+	n.selfChildren = make(map[selfParentKey]*Event)
+	n.votes = make(map[string]map[string]bool)
+	n.orphans = make(map[string]*Event)
+	n.firstRetainedRound = snapshot.Round
+	n.lastRoundReceived = snapshot.LastRoundReceived
+	n.consensusDigests = map[uint32]string{snapshot.LastRoundReceived: snapshot.ConsensusDigest}
+
+	famous := make(map[string]bool)
+	for i := range snapshot.Events {
+		state := snapshot.Events[i].State
+		if state.IsWitness && state.IsFamous && state.Round > snapshot.DigestBaseRound && state.Round <= snapshot.LastRoundReceived {
+			famous[snapshot.Events[i].Event.Hash()] = true
+		}
+	}
+
+	var pending []*Event
+	for i := range snapshot.Events {
+		e := snapshot.Events[i].Event // copy, so that the snapshot is not modified by consensus
+		state := snapshot.Events[i].State
+		if state.RoundReceived == 0 || state.RoundReceived > snapshot.LastRoundReceived {
+			pending = append(pending, &e)
+			continue
+		}
+		e.eventState = EventState{Round: state.Round, IsWitness: state.IsWitness, RoundReceived: state.RoundReceived,
+			ConsensusTimestamp: state.ConsensusTimestamp}
+		if e.RoundReceived <= snapshot.DigestBaseRound {
+			e.Round = min(e.Round, e.RoundReceived)
+			e.IsWitness = false
+		}
+		n.insertInstalledEvent(&e, snapshot.LastRoundReceived, famous)
+		// Events that reached consensus are already applied to the state of the snapshot
+		if n.FirstEventOfNotConsensusIndex[e.Owner] == len(n.Hashgraph[e.Owner])-1 {
+			n.FirstEventOfNotConsensusIndex[e.Owner]++
+		}
+	}
+	for inserted := true; inserted; {
+		inserted = false
+		remaining := pending[:0]
+		for _, e := range pending {
+			if !n.parentsKnown(e) || n.validateParents(e) != nil {
+				remaining = append(remaining, e)
+				continue
+			}
+			n.calculateRound(e)
+			n.insertInstalledEvent(e, snapshot.LastRoundReceived, famous)
+			inserted = true
+		}
+		pending = remaining
+	}
+
+	for id := range n.Hashgraph {
+		n.updateFirstRoundOfFameUndecided(id)
+	}
+	n.needsSnapshot = false
+	n.latestSnapshot = snapshot
+	n.uncertifiedSnapshots = nil
+	return nil
+}
+
+// Inserts an event of an installed snapshot. Fame of the witnesses up to the last round received is decided, the
+// famous ones are certified by the consensus digest.
+func (n *Node) insertInstalledEvent(e *Event, lastRoundReceived uint32, famous map[string]bool) {
+	if e.IsWitness && e.Round <= lastRoundReceived {
+		e.IsFamous = famous[e.Hash()]
+		e.IsFameDecided = true
+	}
+	n.insertEvent(e, false)
+	if e.Round < n.firstRetainedRound {
+		n.firstRetainedRound = e.Round
+	}
+}
+
+// Returns the given events of a member that follow the last one that I know
+func (n *Node) unknownEventsAfter(events []*Event) []*Event {
+	for i := len(events) - 1; i >= 0; i-- {
+		if _, ok := n.Events[events[i].Hash()]; ok {
+			return events[i+1:]
+		}
+	}
+	return events
+}
+
+// Canonical encoding of the snapshot without its signature, and without the signatures over its state which are
+// collected after the owner signs it
+func (s *Snapshot) signedBytes() []byte {
+	unsigned := *s
+	unsigned.Signature = ""
+	unsigned.StateSignatures = nil
+	encoded, err := json.Marshal(unsigned)
+	handleError(err)
+	return encoded
+}
+
+// Returns true if the signature is over the state and the consensus of the snapshot after its last round received
+func (s StateSignature) certifies(snapshot *Snapshot) bool {
+	return s.Round == snapshot.LastRoundReceived && s.ConsensusEventCount == snapshot.ConsensusEventCount &&
+		s.StateHash == hashState(snapshot.State) && s.ConsensusDigest == snapshot.ConsensusDigest
+}
+
+// Canonical encoding of the signature over a state without the signature itself
+func (s StateSignature) signedBytes() []byte {
+	unsigned := s
+	unsigned.Signature = ""
+	encoded, err := json.Marshal(unsigned)
+	handleError(err)
+	return encoded
+}
+
+// Returns the hex encoded SHA-256 hash of a state of the application
+func hashState(state []byte) string {
+	hash := sha256.Sum256(state)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,187 @@
+package hashgraph
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+// A lagging member installs a snapshot only when members with more than 2/3 of the stake signed its state, so a single
+// member can not make it install a state that it made up
+func TestInstallSnapshotRequiresSupermajority(t *testing.T) {
+	random := rand.New(rand.NewSource(3))
+	d := newTestDAG(t, 4, defaultCoinRoundFrequency)
+	for i := 0; i < 300; i++ {
+		creator := random.Intn(4)
+		d.add(t, creator, (creator+1+random.Intn(3))%4)
+	}
+
+	// Every member receives the same events, so it takes the same snapshots and signs the same states
+	nodes := make([]*Node, 4)
+	for i := range nodes {
+		nodes[i] = d.newMemberNode(i, defaultCoinRoundFrequency)
+		nodes[i].RetentionRounds = 2
+		for start := 0; start < len(d.events); start += 10 {
+			receiveEvents(t, nodes[i], d.events[start:min(start+10, len(d.events))])
+		}
+	}
+	var snapshot Snapshot
+	if err := nodes[0].GetLatestSnapshot(true, &snapshot); err != ErrNoSnapshot {
+		t.Fatalf("snapshot is served before a supermajority signed its state: %v", err)
+	}
+	for _, signer := range nodes[1:3] {
+		nodes[0].addStateSignatures(signer.StateSignatures())
+	}
+	if err := nodes[0].GetLatestSnapshot(true, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.StateSignatures) != 3 {
+		t.Fatalf("snapshot is certified by %d signatures, expected 3", len(snapshot.StateSignatures))
+	}
+
+	lagging := d.newMemberNode(3, defaultCoinRoundFrequency)
+	uncertified := *nodes[0].latestSnapshot
+	if err := lagging.InstallSnapshot(&uncertified); err == nil {
+		t.Fatal("snapshot without state signatures is installed")
+	}
+	minority := snapshot
+	minority.StateSignatures = snapshot.StateSignatures[:2]
+	if err := lagging.InstallSnapshot(&minority); err == nil {
+		t.Fatal("snapshot whose state a minority signed is installed")
+	}
+	duplicated := snapshot
+	duplicated.StateSignatures = append(snapshot.StateSignatures[:2:2], snapshot.StateSignatures[0])
+	if err := lagging.InstallSnapshot(&duplicated); err == nil {
+		t.Fatal("snapshot whose state a member signed twice is installed")
+	}
+	// The owner signs a state that it made up, but the others signed a different state
+	madeUp := snapshot
+	madeUp.State = []byte("made up")
+	madeUp.Signature = hex.EncodeToString(ed25519.Sign(d.keys[0], madeUp.signedBytes()))
+	if err := lagging.InstallSnapshot(&madeUp); err == nil {
+		t.Fatal("snapshot with a state that the signatures are not over is installed")
+	}
+	// The owner makes up the fame of a witness, which the state signatures do not cover but the consensus digest does
+	madeUpFame := snapshot
+	madeUpFame.Events = append([]SnapshotEvent(nil), snapshot.Events...)
+	for i, e := range madeUpFame.Events {
+		if e.State.IsFamous && e.State.Round > snapshot.DigestBaseRound && e.State.Round <= snapshot.LastRoundReceived {
+			madeUpFame.Events[i].State.IsFamous = false
+			break
+		}
+	}
+	madeUpFame.Signature = hex.EncodeToString(ed25519.Sign(d.keys[0], madeUpFame.signedBytes()))
+	if err := lagging.InstallSnapshot(&madeUpFame); err == nil {
+		t.Fatal("snapshot with a fame that the consensus digest is not over is installed")
+	}
+	// Rounds of the events after the last round received are not certified, they are calculated again
+	madeUpRound := snapshot
+	madeUpRound.Events = append([]SnapshotEvent(nil), snapshot.Events...)
+	var notReceived string
+	for i, e := range madeUpRound.Events {
+		if e.State.RoundReceived == 0 && e.State.Round > snapshot.LastRoundReceived {
+			madeUpRound.Events[i].State.Round += 5
+			notReceived = e.Event.Hash()
+		}
+	}
+	madeUpRound.Signature = hex.EncodeToString(ed25519.Sign(d.keys[0], madeUpRound.signedBytes()))
+	if err := lagging.InstallSnapshot(&madeUpRound); err != nil {
+		t.Fatal(err)
+	}
+	if lagging.Events[notReceived].Round != nodes[0].Events[notReceived].Round {
+		t.Fatalf("made up round %d of an event is installed, expected %d", lagging.Events[notReceived].Round,
+			nodes[0].Events[notReceived].Round)
+	}
+	if lagging.lastRoundReceived != snapshot.LastRoundReceived {
+		t.Fatalf("last round received is %d after installing the snapshot, expected %d", lagging.lastRoundReceived,
+			snapshot.LastRoundReceived)
+	}
+}
+
+// A member whose latest event has an other-parent that its peers pruned is told that it lags behind, instead of the
+// peer waiting for a snapshot itself. The member installs a snapshot that does not include that event, and its next
+// event builds on its last event in the snapshot with the transactions of the dropped one.
+func TestLaggingMemberRebuildsOnSnapshot(t *testing.T) {
+	random := rand.New(rand.NewSource(5))
+	d := newTestDAG(t, 4, defaultCoinRoundFrequency)
+	d.add(t, 3, 0)
+	d.add(t, 1, 3)
+	lagging := d.newMemberNode(3, defaultCoinRoundFrequency)
+	receiveEvents(t, lagging, d.events)
+	stale := &Event{Owner: d.ids[3], Timestamp: d.clock, Transactions: []Transaction{[]byte("dropped")},
+		SelfParentHash: d.latest[3].Hash(), OtherParentHash: d.latest[1].Hash()}
+	stale.Sign(d.keys[3])
+	receiveEvents(t, lagging, []*Event{stale})
+	for i := 0; i < 300; i++ {
+		creator := random.Intn(3)
+		d.add(t, creator, (creator+1+random.Intn(2))%3)
+	}
+
+	nodes := make([]*Node, 3)
+	for i := range nodes {
+		nodes[i] = d.newMemberNode(i, defaultCoinRoundFrequency)
+		nodes[i].RetentionRounds = 2
+		for start := 0; start < len(d.events); start += 10 {
+			receiveEvents(t, nodes[i], d.events[start:min(start+10, len(d.events))])
+		}
+	}
+	for _, signer := range nodes[1:] {
+		nodes[0].addStateSignatures(signer.StateSignatures())
+	}
+	peer := nodes[0]
+
+	var latest LatestEventsDTO
+	if err := peer.GetLatestEvents(true, &latest); err != nil {
+		t.Fatal(err)
+	}
+	events := SyncEventsDTO{SenderID: lagging.ID, MissingEvents: lagging.MissingEvents(latest),
+		LastRoundReceived: lagging.LastRoundReceived()}
+	var success bool
+	if err := peer.SyncAllEvents(events, &success); !errors.Is(err, ErrSenderLagsBehind) {
+		t.Fatalf("peer that pruned the parents of the events of the sender returned %v", err)
+	}
+	if peer.NeedsSnapshot() {
+		t.Fatal("peer that is ahead of the sender needs a snapshot")
+	}
+
+	lagging.RequestSnapshot()
+	var snapshot Snapshot
+	if err := peer.GetLatestSnapshot(true, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if err := lagging.InstallSnapshot(&snapshot); err != nil {
+		t.Fatal(err)
+	}
+	ownEvents := lagging.Hashgraph[lagging.ID]
+	if lagging.NeedsSnapshot() || ownEvents[len(ownEvents)-1].Hash() != d.latest[3].Hash() {
+		t.Fatal("lagging member does not continue from its last event in the snapshot")
+	}
+
+	if err := lagging.GetLatestEvents(true, &latest); err != nil {
+		t.Fatal(err)
+	}
+	events = SyncEventsDTO{SenderID: peer.ID, MissingEvents: peer.MissingEvents(latest),
+		LastRoundReceived: peer.LastRoundReceived()}
+	if err := lagging.SyncAllEvents(events, &success); err != nil || !success {
+		t.Fatalf("lagging member could not sync after installing the snapshot: %v", err)
+	}
+	ownEvents = lagging.Hashgraph[lagging.ID]
+	next := ownEvents[len(ownEvents)-1]
+	if next.SelfParentHash != d.latest[3].Hash() || len(next.Transactions) != 1 || string(next.Transactions[0]) != "dropped" {
+		t.Fatal("next event of the lagging member does not carry the transactions of its dropped event")
+	}
+
+	if err := peer.GetLatestEvents(true, &latest); err != nil {
+		t.Fatal(err)
+	}
+	events = SyncEventsDTO{SenderID: lagging.ID, MissingEvents: lagging.MissingEvents(latest),
+		LastRoundReceived: lagging.LastRoundReceived()}
+	if err := peer.SyncAllEvents(events, &success); err != nil || !success {
+		t.Fatalf("peer could not insert the events of the member that caught up: %v", err)
+	}
+	if _, ok := peer.Events[next.Hash()]; !ok {
+		t.Fatal("peer does not know the next event of the member that caught up")
+	}
+}
@@ -0,0 +1,282 @@
+package hashgraph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	EventRecord     = "event"     // An event was inserted to the hashgraph
+	FameRecord      = "fame"      // Fame of a witness is decided
+	ConsensusRecord = "consensus" // An event reached consensus
+	RoundRecord     = "round"     // All events received in a round reached consensus
+	SnapshotRecord  = "snapshot"  // A snapshot replaced the hashgraph, or the records before it in a checkpoint
+)
+
+//ErrStoreFailed : Returned by a node whose store failed to persist its records. The node accepts no more events and
+// shares none of its own, since its store would not match its hashgraph after a restart.
+var ErrStoreFailed = errors.New("store failed")
+
+//StoreRecord : An entry of an event store, either an inserted event or a consensus result about an inserted event
+type StoreRecord struct {
+	Kind               string    `json:"kind"`                          // One of EventRecord, FameRecord, ConsensusRecord, RoundRecord or SnapshotRecord
+	Event              *Event    `json:"event,omitempty"`               // The inserted event, only for event records
+	Snapshot           *Snapshot `json:"snapshot,omitempty"`            // The installed snapshot, only for snapshot records
+	Hash               string    `json:"hash,omitempty"`                // Hash of the event that the consensus result is about
+	IsFamous           bool      `json:"is_famous,omitempty"`           // Decided fame of the witness, only for fame records
+	RoundReceived      uint32    `json:"round_received,omitempty"`      // Only for consensus and round records
+	ConsensusTimestamp time.Time `json:"consensus_timestamp,omitempty"` // Only for consensus records
+}
+
+//EventStore : Persists the records of a node in the order they are appended, so that the node can recover after a restart
+type EventStore interface {
+	Append(records []StoreRecord) error  // Appending must be durable once it returns, the records are flushed together
+	Checkpoint(snapshot *Snapshot) error // Replaces every record with a snapshot record, must be durable once it returns
+	Load() ([]StoreRecord, error)        // Returns every record appended since the last checkpoint, in order
+	Close() error
+}
+
+//FileEventStore : An append-only log file of JSON encoded records, one record per line
+type FileEventStore struct {
+	sync.Mutex
+	path string   // path of the log file
+	file *os.File // log file, opened for appending
+}
+
+//NewFileEventStore : Opens the log file at the given path, creating it if it does not exist
+func NewFileEventStore(path string) (*FileEventStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &FileEventStore{path: path, file: file}, nil
+}
+
+//Append : Writes the records to the end of the log and flushes them to the disk once
+func (s *FileEventStore) Append(records []StoreRecord) error {
+	lines, err := encodeRecords(records)
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+	if _, err = s.file.Write(lines); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+//Checkpoint : Replaces the log with a new log that only has a record of the snapshot, so that the records before the
+// snapshot are not replayed on recovery. The new log is flushed to the disk before it is renamed over the old one, so a
+// crash leaves either of them intact.
+func (s *FileEventStore) Checkpoint(snapshot *Snapshot) error {
+	lines, err := encodeRecords([]StoreRecord{{Kind: SnapshotRecord, Snapshot: snapshot}})
+	if err != nil {
+		return err
+	}
+	s.Lock()
+	defer s.Unlock()
+
+	checkpointPath := s.path + ".checkpoint"
+	file, err := os.OpenFile(checkpointPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(lines); err == nil {
+		err = file.Sync()
+	}
+	if err == nil {
+		err = os.Rename(checkpointPath, s.path)
+	}
+	if err == nil {
+		err = syncDir(filepath.Dir(s.path))
+	}
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	_ = s.file.Close() // the old log is already replaced
+	s.file = file
+	return nil
+}
+
+//Load : Reads all records of the log. A partially written last line, which is left by a crash during Append, is ignored.
+func (s *FileEventStore) Load() ([]StoreRecord, error) {
+	s.Lock()
+	defer s.Unlock()
+	if _, err := s.file.Seek(0, 0); err != nil {
+		return nil, err
+	}
+
+	var records []StoreRecord
+	scanner := bufio.NewScanner(s.file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var record StoreRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			break
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+//Close : Closes the log file
+func (s *FileEventStore) Close() error {
+	s.Lock()
+	defer s.Unlock()
+	return s.file.Close()
+}
+
+// Encodes the records as JSON lines
+func encodeRecords(records []StoreRecord) ([]byte, error) {
+	var lines bytes.Buffer
+	encoder := json.NewEncoder(&lines)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, err
+		}
+	}
+	return lines.Bytes(), nil
+}
+
+// Flushes the entries of the directory to the disk, so that a renamed file keeps its new name after a crash
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = dir.Close()
+	}()
+	return dir.Sync()
+}
+
+//Recover : Rebuilds the hashgraph from the records of the node's store. Rounds, witnesses, first rounds of fame undecided and first
+// events of not consensus are derived from the recovered events, then consensus continues from where it was left off.
+// Consensus records are appended in consensus order, so the consensus events are recovered and delivered to the
+// application in the same order. Events of a round are only recovered as consensus events if the round was completed.
+func (n *Node) Recover() error {
+	records, err := n.Store.Load()
+	if err != nil {
+		return err
+	}
+
+	var receivedEvents []*Event // events that reached consensus in a round that is not completed yet
+	for _, record := range records {
+		switch record.Kind {
+		case EventRecord:
+			if _, ok := n.Events[record.Event.Hash()]; !ok {
+				n.calculateRound(record.Event) // events are stored without their state, in the order they were inserted
+				n.insertEvent(record.Event, false)
+			}
+		case FameRecord:
+			if witness, ok := n.Events[record.Hash]; ok {
+				witness.IsFamous = record.IsFamous
+				witness.IsFameDecided = true
+			}
+		case ConsensusRecord:
+			if e, ok := n.Events[record.Hash]; ok && e.RoundReceived == 0 {
+				e.RoundReceived = record.RoundReceived
+				e.ConsensusTimestamp = record.ConsensusTimestamp
+				receivedEvents = append(receivedEvents, e)
+			}
+		case RoundRecord:
+			for _, e := range receivedEvents {
+				n.ConsensusEvents = append(n.ConsensusEvents, e)
+				n.deliver(e)
+			}
+			for id := range n.Hashgraph {
+				n.updateFirstEventOfNotConsensusIndex(id)
+			}
+			n.lastRoundReceived = record.RoundReceived
+			n.advanceConsensusDigest(record.RoundReceived, receivedEvents)
+			receivedEvents = nil
+			n.commit(record.RoundReceived)
+		case SnapshotRecord:
+			if err := n.installSnapshot(record.Snapshot); err != nil {
+				return err
+			}
+		}
+	}
+
+	// The node stopped in the middle of a round, these events will reach consensus again
+	for _, e := range receivedEvents {
+		e.RoundReceived = 0
+		e.ConsensusTimestamp = time.Unix(0, 0)
+	}
+
+	for id := range n.Hashgraph {
+		n.updateFirstRoundOfFameUndecided(id)
+	}
+
+	// Catch up with the decisions that were not persisted before the restart
+	n.DecideFame()
+	n.FindOrder()
+	n.PruneEvents()
+	return n.flushRecords()
+}
+
+//CloseStore : Closes the store of the node, the records that are appended later are not persisted. Does nothing if the
+// node has no store.
+func (n *Node) CloseStore() error {
+	n.RWMutex.Lock()
+	defer n.RWMutex.Unlock()
+
+	if n.Store == nil {
+		return nil
+	}
+	flushErr := n.flushRecords()
+	err := n.Store.Close()
+	n.Store = nil
+	if flushErr != nil {
+		return flushErr
+	}
+	return err
+}
+
+//StoreError : Returns the failure of the store of the node, which wraps ErrStoreFailed, nil if the store did not fail
+func (n *Node) StoreError() error {
+	n.RWMutex.RLock()
+	defer n.RWMutex.RUnlock()
+
+	return n.storeErr
+}
+
+// Buffers the record until the records of the current sync are appended to the store of the node, if it has one
+func (n *Node) persist(record StoreRecord) {
+	if n.Store != nil {
+		n.pendingRecords = append(n.pendingRecords, record)
+	}
+}
+
+// Appends the buffered records to the store of the node with a single flush to the disk, returns the failure of the
+// store. Nothing is appended after the store fails once.
+func (n *Node) flushRecords() error {
+	if n.Store != nil && len(n.pendingRecords) > 0 && n.storeErr == nil {
+		if err := n.Store.Append(n.pendingRecords); err != nil {
+			n.storeErr = fmt.Errorf("%w: %v", ErrStoreFailed, err)
+		}
+	}
+	n.pendingRecords = nil
+	return n.storeErr
+}
+
+// Replaces the records in the store of the node with the snapshot, which covers the buffered records too. Returns the
+// failure of the store.
+func (n *Node) checkpoint(snapshot *Snapshot) error {
+	if n.Store != nil && n.storeErr == nil {
+		if err := n.Store.Checkpoint(snapshot); err != nil {
+			n.storeErr = fmt.Errorf("%w: %v", ErrStoreFailed, err)
+		}
+	}
+	n.pendingRecords = nil
+	return n.storeErr
+}
@@ -0,0 +1,115 @@
+package hashgraph
+
+import (
+	"errors"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+// A node that prunes its events checkpoints its store at every snapshot, so it recovers from the latest snapshot
+// instead of replaying every record since the beginning
+func TestRecoverFromCheckpoint(t *testing.T) {
+	random := rand.New(rand.NewSource(2))
+	d := newTestDAG(t, 4, defaultCoinRoundFrequency)
+	for i := 0; i < 300; i++ {
+		creator := random.Intn(4)
+		d.add(t, creator, (creator+1+random.Intn(3))%4)
+	}
+
+	path := filepath.Join(t.TempDir(), "events.log")
+	store, err := NewFileEventStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := d.newNode(defaultCoinRoundFrequency)
+	n.RetentionRounds = 2
+	n.Store = store
+	for start := 0; start < len(d.events); start += 10 {
+		receiveEvents(t, n, d.events[start:min(start+10, len(d.events))])
+	}
+	if n.latestSnapshot == nil {
+		t.Fatal("no snapshot is taken")
+	}
+	records, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) == 0 || records[0].Kind != SnapshotRecord || records[0].Snapshot.Round != n.latestSnapshot.Round {
+		t.Fatal("store does not start with the latest snapshot")
+	}
+	if len(records) >= len(d.events) {
+		t.Fatalf("store has %d records after a checkpoint, more than the %d events", len(records), len(d.events))
+	}
+	if err := n.CloseStore(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = NewFileEventStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recovered := d.newNode(defaultCoinRoundFrequency)
+	recovered.RetentionRounds = 2
+	recovered.Store = store
+	if err := recovered.Recover(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = recovered.CloseStore()
+	}()
+	if recovered.lastRoundReceived != n.lastRoundReceived || len(recovered.Events) != len(n.Events) {
+		t.Fatalf("recovered round %d with %d events, expected round %d with %d events", recovered.lastRoundReceived,
+			len(recovered.Events), n.lastRoundReceived, len(n.Events))
+	}
+	count := recovered.PrunedConsensusCount + len(recovered.ConsensusEvents)
+	if expected := n.PrunedConsensusCount + len(n.ConsensusEvents); count != expected {
+		t.Fatalf("%d events reached consensus after recovery, expected %d", count, expected)
+	}
+	for i, e := range recovered.ConsensusEvents {
+		position := recovered.PrunedConsensusCount + i - n.PrunedConsensusCount
+		if position >= 0 && e.Hash() != n.ConsensusEvents[position].Hash() {
+			t.Fatalf("consensus event %d is %s after recovery, expected %s", i, e.Hash(), n.ConsensusEvents[position].Hash())
+		}
+	}
+}
+
+// Store whose disk is full
+type failingStore struct{}
+
+func (failingStore) Append([]StoreRecord) error   { return errors.New("no space left on device") }
+func (failingStore) Checkpoint(*Snapshot) error   { return errors.New("no space left on device") }
+func (failingStore) Load() ([]StoreRecord, error) { return nil, nil }
+func (failingStore) Close() error                 { return nil }
+
+// A node whose store fails reports the failure from the sync instead of crashing, then accepts no more events and
+// shares none of its own, since they are not persisted
+func TestSyncReportsStoreFailure(t *testing.T) {
+	d := newTestDAG(t, 4, defaultCoinRoundFrequency)
+	for i := 0; i < 20; i++ {
+		d.add(t, i%4, (i+1)%4)
+	}
+	missingEvents := make(map[MemberID][]*Event)
+	for _, e := range d.events {
+		c := *e
+		missingEvents[e.Owner] = append(missingEvents[e.Owner], &c)
+	}
+
+	n := d.newNode(defaultCoinRoundFrequency)
+	n.Store = failingStore{}
+	var success bool
+	err := n.SyncAllEvents(SyncEventsDTO{SenderID: d.ids[1], MissingEvents: missingEvents}, &success)
+	if !errors.Is(err, ErrStoreFailed) || success {
+		t.Fatalf("sync succeeded with a failing store, the error is %v", err)
+	}
+	if err := n.SyncAllEvents(SyncEventsDTO{SenderID: d.ids[2]}, &success); !errors.Is(err, ErrStoreFailed) {
+		t.Fatalf("node accepted events after its store failed, the error is %v", err)
+	}
+	var latestEvents LatestEventsDTO
+	if err := n.GetLatestEvents(true, &latestEvents); !errors.Is(err, ErrStoreFailed) {
+		t.Fatalf("node shared its events after its store failed, the error is %v", err)
+	}
+	if err := n.CloseStore(); !errors.Is(err, ErrStoreFailed) {
+		t.Fatalf("closing the store did not report its failure, the error is %v", err)
+	}
+}
@@ -0,0 +1,1066 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: hashgraph.proto
+
+// Wire format of the gossip between the members of a hashgraph. Every request and response carries the protocol
+// version of its sender as its first field, so that members reject the messages of another version instead of
+// decoding them with the wrong schema.
+
+package wire
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// An event as it is created and signed by its owner
+type Event struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Owner           string                 `protobuf:"bytes,1,opt,name=owner,proto3" json:"owner,omitempty"`                                              // ID of the member that created the event
+	Signature       string                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`                                      // Signature of the owner over the canonical encoding of the event
+	SelfParentHash  string                 `protobuf:"bytes,3,opt,name=self_parent_hash,json=selfParentHash,proto3" json:"self_parent_hash,omitempty"`    // Hash of the previous event of the owner, empty for the initial event
+	OtherParentHash string                 `protobuf:"bytes,4,opt,name=other_parent_hash,json=otherParentHash,proto3" json:"other_parent_hash,omitempty"` // Hash of the last event of the peer that gossiped to the owner, empty for the initial event
+	Timestamp       *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`                                      // Datetime of creation
+	Transactions    [][]byte               `protobuf:"bytes,6,rep,name=transactions,proto3" json:"transactions,omitempty"`                                // Opaque payloads, only interpreted by the application
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_hashgraph_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Event) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *Event) GetSelfParentHash() string {
+	if x != nil {
+		return x.SelfParentHash
+	}
+	return ""
+}
+
+func (x *Event) GetOtherParentHash() string {
+	if x != nil {
+		return x.OtherParentHash
+	}
+	return ""
+}
+
+func (x *Event) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *Event) GetTransactions() [][]byte {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+// Consensus fields of an event, which are only sent in a snapshot since they can not be calculated again without the
+// pruned ancestors of the event
+type EventState struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Round              uint32                 `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	IsWitness          bool                   `protobuf:"varint,2,opt,name=is_witness,json=isWitness,proto3" json:"is_witness,omitempty"`
+	IsFamous           bool                   `protobuf:"varint,3,opt,name=is_famous,json=isFamous,proto3" json:"is_famous,omitempty"`
+	IsFameDecided      bool                   `protobuf:"varint,4,opt,name=is_fame_decided,json=isFameDecided,proto3" json:"is_fame_decided,omitempty"`
+	RoundReceived      uint32                 `protobuf:"varint,5,opt,name=round_received,json=roundReceived,proto3" json:"round_received,omitempty"`
+	ConsensusTimestamp *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=consensus_timestamp,json=consensusTimestamp,proto3" json:"consensus_timestamp,omitempty"`
+	Latency            *durationpb.Duration   `protobuf:"bytes,7,opt,name=latency,proto3" json:"latency,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *EventState) Reset() {
+	*x = EventState{}
+	mi := &file_hashgraph_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventState) ProtoMessage() {}
+
+func (x *EventState) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventState.ProtoReflect.Descriptor instead.
+func (*EventState) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EventState) GetRound() uint32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *EventState) GetIsWitness() bool {
+	if x != nil {
+		return x.IsWitness
+	}
+	return false
+}
+
+func (x *EventState) GetIsFamous() bool {
+	if x != nil {
+		return x.IsFamous
+	}
+	return false
+}
+
+func (x *EventState) GetIsFameDecided() bool {
+	if x != nil {
+		return x.IsFameDecided
+	}
+	return false
+}
+
+func (x *EventState) GetRoundReceived() uint32 {
+	if x != nil {
+		return x.RoundReceived
+	}
+	return 0
+}
+
+func (x *EventState) GetConsensusTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ConsensusTimestamp
+	}
+	return nil
+}
+
+func (x *EventState) GetLatency() *durationpb.Duration {
+	if x != nil {
+		return x.Latency
+	}
+	return nil
+}
+
+type SnapshotEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	State         *EventState            `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotEvent) Reset() {
+	*x = SnapshotEvent{}
+	mi := &file_hashgraph_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotEvent) ProtoMessage() {}
+
+func (x *SnapshotEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotEvent.ProtoReflect.Descriptor instead.
+func (*SnapshotEvent) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SnapshotEvent) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *SnapshotEvent) GetState() *EventState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+// Signature of a member over the state of the application after it committed a round
+type StateSignature struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Round               uint32                 `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	ConsensusEventCount int64                  `protobuf:"varint,2,opt,name=consensus_event_count,json=consensusEventCount,proto3" json:"consensus_event_count,omitempty"`
+	StateHash           string                 `protobuf:"bytes,3,opt,name=state_hash,json=stateHash,proto3" json:"state_hash,omitempty"` // Hex encoded SHA-256 hash of the state
+	Signer              string                 `protobuf:"bytes,4,opt,name=signer,proto3" json:"signer,omitempty"`                        // ID of the member that signed the state
+	Signature           string                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	ConsensusDigest     string                 `protobuf:"bytes,6,opt,name=consensus_digest,json=consensusDigest,proto3" json:"consensus_digest,omitempty"` // Digest of the famous witnesses and the received events up to the round
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *StateSignature) Reset() {
+	*x = StateSignature{}
+	mi := &file_hashgraph_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StateSignature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateSignature) ProtoMessage() {}
+
+func (x *StateSignature) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateSignature.ProtoReflect.Descriptor instead.
+func (*StateSignature) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StateSignature) GetRound() uint32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *StateSignature) GetConsensusEventCount() int64 {
+	if x != nil {
+		return x.ConsensusEventCount
+	}
+	return 0
+}
+
+func (x *StateSignature) GetStateHash() string {
+	if x != nil {
+		return x.StateHash
+	}
+	return ""
+}
+
+func (x *StateSignature) GetSigner() string {
+	if x != nil {
+		return x.Signer
+	}
+	return ""
+}
+
+func (x *StateSignature) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *StateSignature) GetConsensusDigest() string {
+	if x != nil {
+		return x.ConsensusDigest
+	}
+	return ""
+}
+
+// State of consensus at a round boundary, signed by the member that took it
+type Snapshot struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Round               uint32                 `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	Owner               string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	State               []byte                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"` // State of the application
+	ConsensusEventCount int64                  `protobuf:"varint,4,opt,name=consensus_event_count,json=consensusEventCount,proto3" json:"consensus_event_count,omitempty"`
+	LastRoundReceived   uint32                 `protobuf:"varint,5,opt,name=last_round_received,json=lastRoundReceived,proto3" json:"last_round_received,omitempty"`
+	Events              []*SnapshotEvent       `protobuf:"bytes,6,rep,name=events,proto3" json:"events,omitempty"`                                                                                                                          // Events that are not pruned, in the order they were inserted
+	PrunedEventCount    map[string]int64       `protobuf:"bytes,7,rep,name=pruned_event_count,json=prunedEventCount,proto3" json:"pruned_event_count,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"` // map of member ID -> number of pruned events of that member
+	Signature           string                 `protobuf:"bytes,9,opt,name=signature,proto3" json:"signature,omitempty"`
+	StateSignatures     []*StateSignature      `protobuf:"bytes,10,rep,name=state_signatures,json=stateSignatures,proto3" json:"state_signatures,omitempty"`    // Signatures of a supermajority of the stake over the state
+	ConsensusDigest     string                 `protobuf:"bytes,11,opt,name=consensus_digest,json=consensusDigest,proto3" json:"consensus_digest,omitempty"`    // Digest of the consensus up to the last round received
+	DigestBaseRound     uint32                 `protobuf:"varint,12,opt,name=digest_base_round,json=digestBaseRound,proto3" json:"digest_base_round,omitempty"` // Round that the consensus of the events is chained to
+	DigestBase          string                 `protobuf:"bytes,13,opt,name=digest_base,json=digestBase,proto3" json:"digest_base,omitempty"`                   // Digest of the consensus up to the base round
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
+	mi := &file_hashgraph_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Snapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Snapshot) ProtoMessage() {}
+
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Snapshot) GetRound() uint32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *Snapshot) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *Snapshot) GetState() []byte {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *Snapshot) GetConsensusEventCount() int64 {
+	if x != nil {
+		return x.ConsensusEventCount
+	}
+	return 0
+}
+
+func (x *Snapshot) GetLastRoundReceived() uint32 {
+	if x != nil {
+		return x.LastRoundReceived
+	}
+	return 0
+}
+
+func (x *Snapshot) GetEvents() []*SnapshotEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *Snapshot) GetPrunedEventCount() map[string]int64 {
+	if x != nil {
+		return x.PrunedEventCount
+	}
+	return nil
+}
+
+func (x *Snapshot) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *Snapshot) GetStateSignatures() []*StateSignature {
+	if x != nil {
+		return x.StateSignatures
+	}
+	return nil
+}
+
+func (x *Snapshot) GetConsensusDigest() string {
+	if x != nil {
+		return x.ConsensusDigest
+	}
+	return ""
+}
+
+func (x *Snapshot) GetDigestBaseRound() uint32 {
+	if x != nil {
+		return x.DigestBaseRound
+	}
+	return 0
+}
+
+func (x *Snapshot) GetDigestBase() string {
+	if x != nil {
+		return x.DigestBase
+	}
+	return ""
+}
+
+type EventList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Events        []*Event               `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventList) Reset() {
+	*x = EventList{}
+	mi := &file_hashgraph_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventList) ProtoMessage() {}
+
+func (x *EventList) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventList.ProtoReflect.Descriptor instead.
+func (*EventList) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *EventList) GetEvents() []*Event {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type HashList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hashes        []string               `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HashList) Reset() {
+	*x = HashList{}
+	mi := &file_hashgraph_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HashList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HashList) ProtoMessage() {}
+
+func (x *HashList) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HashList.ProtoReflect.Descriptor instead.
+func (*HashList) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *HashList) GetHashes() []string {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+type GetLatestEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestEventsRequest) Reset() {
+	*x = GetLatestEventsRequest{}
+	mi := &file_hashgraph_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestEventsRequest) ProtoMessage() {}
+
+func (x *GetLatestEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestEventsRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestEventsRequest) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetLatestEventsRequest) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type GetLatestEventsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Tips          map[string]*HashList   `protobuf:"bytes,2,rep,name=tips,proto3" json:"tips,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // map of member ID -> hashes of the latest events of that member, more than one for a member that forked
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestEventsResponse) Reset() {
+	*x = GetLatestEventsResponse{}
+	mi := &file_hashgraph_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestEventsResponse) ProtoMessage() {}
+
+func (x *GetLatestEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestEventsResponse.ProtoReflect.Descriptor instead.
+func (*GetLatestEventsResponse) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetLatestEventsResponse) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *GetLatestEventsResponse) GetTips() map[string]*HashList {
+	if x != nil {
+		return x.Tips
+	}
+	return nil
+}
+
+type SyncEventsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Version           uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	SenderId          string                 `protobuf:"bytes,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`                                                                                          // ID of the member that sends the events
+	MissingEvents     map[string]*EventList  `protobuf:"bytes,3,rep,name=missing_events,json=missingEvents,proto3" json:"missing_events,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // map of member ID -> events of that member that the receiver does not know
+	StateSignatures   []*StateSignature      `protobuf:"bytes,4,rep,name=state_signatures,json=stateSignatures,proto3" json:"state_signatures,omitempty"`                                                                     // signatures of the sender over its state after the rounds it committed last
+	LastRoundReceived uint32                 `protobuf:"varint,5,opt,name=last_round_received,json=lastRoundReceived,proto3" json:"last_round_received,omitempty"`                                                            // last round that the sender received events in
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *SyncEventsRequest) Reset() {
+	*x = SyncEventsRequest{}
+	mi := &file_hashgraph_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncEventsRequest) ProtoMessage() {}
+
+func (x *SyncEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncEventsRequest.ProtoReflect.Descriptor instead.
+func (*SyncEventsRequest) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SyncEventsRequest) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SyncEventsRequest) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
+	}
+	return ""
+}
+
+func (x *SyncEventsRequest) GetMissingEvents() map[string]*EventList {
+	if x != nil {
+		return x.MissingEvents
+	}
+	return nil
+}
+
+func (x *SyncEventsRequest) GetStateSignatures() []*StateSignature {
+	if x != nil {
+		return x.StateSignatures
+	}
+	return nil
+}
+
+func (x *SyncEventsRequest) GetLastRoundReceived() uint32 {
+	if x != nil {
+		return x.LastRoundReceived
+	}
+	return 0
+}
+
+type SyncEventsResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Version             uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Success             bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`                                                      // false if the receiver needs a snapshot to insert the events
+	SenderNeedsSnapshot bool                   `protobuf:"varint,3,opt,name=sender_needs_snapshot,json=senderNeedsSnapshot,proto3" json:"sender_needs_snapshot,omitempty"` // true if the receiver pruned the parents of the events, so the sender needs a snapshot
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *SyncEventsResponse) Reset() {
+	*x = SyncEventsResponse{}
+	mi := &file_hashgraph_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SyncEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SyncEventsResponse) ProtoMessage() {}
+
+func (x *SyncEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SyncEventsResponse.ProtoReflect.Descriptor instead.
+func (*SyncEventsResponse) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SyncEventsResponse) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *SyncEventsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SyncEventsResponse) GetSenderNeedsSnapshot() bool {
+	if x != nil {
+		return x.SenderNeedsSnapshot
+	}
+	return false
+}
+
+type GetLatestSnapshotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestSnapshotRequest) Reset() {
+	*x = GetLatestSnapshotRequest{}
+	mi := &file_hashgraph_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestSnapshotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestSnapshotRequest) ProtoMessage() {}
+
+func (x *GetLatestSnapshotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestSnapshotRequest.ProtoReflect.Descriptor instead.
+func (*GetLatestSnapshotRequest) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetLatestSnapshotRequest) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type GetLatestSnapshotResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       uint32                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	Snapshot      *Snapshot              `protobuf:"bytes,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLatestSnapshotResponse) Reset() {
+	*x = GetLatestSnapshotResponse{}
+	mi := &file_hashgraph_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLatestSnapshotResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLatestSnapshotResponse) ProtoMessage() {}
+
+func (x *GetLatestSnapshotResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_hashgraph_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLatestSnapshotResponse.ProtoReflect.Descriptor instead.
+func (*GetLatestSnapshotResponse) Descriptor() ([]byte, []int) {
+	return file_hashgraph_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetLatestSnapshotResponse) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *GetLatestSnapshotResponse) GetSnapshot() *Snapshot {
+	if x != nil {
+		return x.Snapshot
+	}
+	return nil
+}
+
+var File_hashgraph_proto protoreflect.FileDescriptor
+
+const file_hashgraph_proto_rawDesc = "" +
+	"\n" +
+	"\x0fhashgraph.proto\x12\thashgraph\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xef\x01\n" +
+	"\x05Event\x12\x14\n" +
+	"\x05owner\x18\x01 \x01(\tR\x05owner\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\tR\tsignature\x12(\n" +
+	"\x10self_parent_hash\x18\x03 \x01(\tR\x0eselfParentHash\x12*\n" +
+	"\x11other_parent_hash\x18\x04 \x01(\tR\x0fotherParentHash\x128\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\"\n" +
+	"\ftransactions\x18\x06 \x03(\fR\ftransactions\"\xaf\x02\n" +
+	"\n" +
+	"EventState\x12\x14\n" +
+	"\x05round\x18\x01 \x01(\rR\x05round\x12\x1d\n" +
+	"\n" +
+	"is_witness\x18\x02 \x01(\bR\tisWitness\x12\x1b\n" +
+	"\tis_famous\x18\x03 \x01(\bR\bisFamous\x12&\n" +
+	"\x0fis_fame_decided\x18\x04 \x01(\bR\risFameDecided\x12%\n" +
+	"\x0eround_received\x18\x05 \x01(\rR\rroundReceived\x12K\n" +
+	"\x13consensus_timestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x12consensusTimestamp\x123\n" +
+	"\alatency\x18\a \x01(\v2\x19.google.protobuf.DurationR\alatency\"d\n" +
+	"\rSnapshotEvent\x12&\n" +
+	"\x05event\x18\x01 \x01(\v2\x10.hashgraph.EventR\x05event\x12+\n" +
+	"\x05state\x18\x02 \x01(\v2\x15.hashgraph.EventStateR\x05state\"\xda\x01\n" +
+	"\x0eStateSignature\x12\x14\n" +
+	"\x05round\x18\x01 \x01(\rR\x05round\x122\n" +
+	"\x15consensus_event_count\x18\x02 \x01(\x03R\x13consensusEventCount\x12\x1d\n" +
+	"\n" +
+	"state_hash\x18\x03 \x01(\tR\tstateHash\x12\x16\n" +
+	"\x06signer\x18\x04 \x01(\tR\x06signer\x12\x1c\n" +
+	"\tsignature\x18\x05 \x01(\tR\tsignature\x12)\n" +
+	"\x10consensus_digest\x18\x06 \x01(\tR\x0fconsensusDigest\"\xe2\x04\n" +
+	"\bSnapshot\x12\x14\n" +
+	"\x05round\x18\x01 \x01(\rR\x05round\x12\x14\n" +
+	"\x05owner\x18\x02 \x01(\tR\x05owner\x12\x14\n" +
+	"\x05state\x18\x03 \x01(\fR\x05state\x122\n" +
+	"\x15consensus_event_count\x18\x04 \x01(\x03R\x13consensusEventCount\x12.\n" +
+	"\x13last_round_received\x18\x05 \x01(\rR\x11lastRoundReceived\x120\n" +
+	"\x06events\x18\x06 \x03(\v2\x18.hashgraph.SnapshotEventR\x06events\x12W\n" +
+	"\x12pruned_event_count\x18\a \x03(\v2).hashgraph.Snapshot.PrunedEventCountEntryR\x10prunedEventCount\x12\x1c\n" +
+	"\tsignature\x18\t \x01(\tR\tsignature\x12D\n" +
+	"\x10state_signatures\x18\n" +
+	" \x03(\v2\x19.hashgraph.StateSignatureR\x0fstateSignatures\x12)\n" +
+	"\x10consensus_digest\x18\v \x01(\tR\x0fconsensusDigest\x12*\n" +
+	"\x11digest_base_round\x18\f \x01(\rR\x0fdigestBaseRound\x12\x1f\n" +
+	"\vdigest_base\x18\r \x01(\tR\n" +
+	"digestBase\x1aC\n" +
+	"\x15PrunedEventCountEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x03R\x05value:\x028\x01J\x04\b\b\x10\t\"5\n" +
+	"\tEventList\x12(\n" +
+	"\x06events\x18\x01 \x03(\v2\x10.hashgraph.EventR\x06events\"\"\n" +
+	"\bHashList\x12\x16\n" +
+	"\x06hashes\x18\x01 \x03(\tR\x06hashes\"2\n" +
+	"\x16GetLatestEventsRequest\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\"\xc3\x01\n" +
+	"\x17GetLatestEventsResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\x12@\n" +
+	"\x04tips\x18\x02 \x03(\v2,.hashgraph.GetLatestEventsResponse.TipsEntryR\x04tips\x1aL\n" +
+	"\tTipsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12)\n" +
+	"\x05value\x18\x02 \x01(\v2\x13.hashgraph.HashListR\x05value:\x028\x01\"\xf0\x02\n" +
+	"\x11SyncEventsRequest\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\x12\x1b\n" +
+	"\tsender_id\x18\x02 \x01(\tR\bsenderId\x12V\n" +
+	"\x0emissing_events\x18\x03 \x03(\v2/.hashgraph.SyncEventsRequest.MissingEventsEntryR\rmissingEvents\x12D\n" +
+	"\x10state_signatures\x18\x04 \x03(\v2\x19.hashgraph.StateSignatureR\x0fstateSignatures\x12.\n" +
+	"\x13last_round_received\x18\x05 \x01(\rR\x11lastRoundReceived\x1aV\n" +
+	"\x12MissingEventsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.hashgraph.EventListR\x05value:\x028\x01\"|\n" +
+	"\x12SyncEventsResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x122\n" +
+	"\x15sender_needs_snapshot\x18\x03 \x01(\bR\x13senderNeedsSnapshot\"4\n" +
+	"\x18GetLatestSnapshotRequest\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\"f\n" +
+	"\x19GetLatestSnapshotResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\rR\aversion\x12/\n" +
+	"\bsnapshot\x18\x02 \x01(\v2\x13.hashgraph.SnapshotR\bsnapshot2\x8d\x02\n" +
+	"\x06Gossip\x12X\n" +
+	"\x0fGetLatestEvents\x12!.hashgraph.GetLatestEventsRequest\x1a\".hashgraph.GetLatestEventsResponse\x12I\n" +
+	"\n" +
+	"SyncEvents\x12\x1c.hashgraph.SyncEventsRequest\x1a\x1d.hashgraph.SyncEventsResponse\x12^\n" +
+	"\x11GetLatestSnapshot\x12#.hashgraph.GetLatestSnapshotRequest\x1a$.hashgraph.GetLatestSnapshotResponseB\tZ\a../wireb\x06proto3"
+
+var (
+	file_hashgraph_proto_rawDescOnce sync.Once
+	file_hashgraph_proto_rawDescData []byte
+)
+
+func file_hashgraph_proto_rawDescGZIP() []byte {
+	file_hashgraph_proto_rawDescOnce.Do(func() {
+		file_hashgraph_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_hashgraph_proto_rawDesc), len(file_hashgraph_proto_rawDesc)))
+	})
+	return file_hashgraph_proto_rawDescData
+}
+
+var file_hashgraph_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_hashgraph_proto_goTypes = []any{
+	(*Event)(nil),                     // 0: hashgraph.Event
+	(*EventState)(nil),                // 1: hashgraph.EventState
+	(*SnapshotEvent)(nil),             // 2: hashgraph.SnapshotEvent
+	(*StateSignature)(nil),            // 3: hashgraph.StateSignature
+	(*Snapshot)(nil),                  // 4: hashgraph.Snapshot
+	(*EventList)(nil),                 // 5: hashgraph.EventList
+	(*HashList)(nil),                  // 6: hashgraph.HashList
+	(*GetLatestEventsRequest)(nil),    // 7: hashgraph.GetLatestEventsRequest
+	(*GetLatestEventsResponse)(nil),   // 8: hashgraph.GetLatestEventsResponse
+	(*SyncEventsRequest)(nil),         // 9: hashgraph.SyncEventsRequest
+	(*SyncEventsResponse)(nil),        // 10: hashgraph.SyncEventsResponse
+	(*GetLatestSnapshotRequest)(nil),  // 11: hashgraph.GetLatestSnapshotRequest
+	(*GetLatestSnapshotResponse)(nil), // 12: hashgraph.GetLatestSnapshotResponse
+	nil,                               // 13: hashgraph.Snapshot.PrunedEventCountEntry
+	nil,                               // 14: hashgraph.GetLatestEventsResponse.TipsEntry
+	nil,                               // 15: hashgraph.SyncEventsRequest.MissingEventsEntry
+	(*timestamppb.Timestamp)(nil),     // 16: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),       // 17: google.protobuf.Duration
+}
+var file_hashgraph_proto_depIdxs = []int32{
+	16, // 0: hashgraph.Event.timestamp:type_name -> google.protobuf.Timestamp
+	16, // 1: hashgraph.EventState.consensus_timestamp:type_name -> google.protobuf.Timestamp
+	17, // 2: hashgraph.EventState.latency:type_name -> google.protobuf.Duration
+	0,  // 3: hashgraph.SnapshotEvent.event:type_name -> hashgraph.Event
+	1,  // 4: hashgraph.SnapshotEvent.state:type_name -> hashgraph.EventState
+	2,  // 5: hashgraph.Snapshot.events:type_name -> hashgraph.SnapshotEvent
+	13, // 6: hashgraph.Snapshot.pruned_event_count:type_name -> hashgraph.Snapshot.PrunedEventCountEntry
+	3,  // 7: hashgraph.Snapshot.state_signatures:type_name -> hashgraph.StateSignature
+	0,  // 8: hashgraph.EventList.events:type_name -> hashgraph.Event
+	14, // 9: hashgraph.GetLatestEventsResponse.tips:type_name -> hashgraph.GetLatestEventsResponse.TipsEntry
+	15, // 10: hashgraph.SyncEventsRequest.missing_events:type_name -> hashgraph.SyncEventsRequest.MissingEventsEntry
+	3,  // 11: hashgraph.SyncEventsRequest.state_signatures:type_name -> hashgraph.StateSignature
+	4,  // 12: hashgraph.GetLatestSnapshotResponse.snapshot:type_name -> hashgraph.Snapshot
+	6,  // 13: hashgraph.GetLatestEventsResponse.TipsEntry.value:type_name -> hashgraph.HashList
+	5,  // 14: hashgraph.SyncEventsRequest.MissingEventsEntry.value:type_name -> hashgraph.EventList
+	7,  // 15: hashgraph.Gossip.GetLatestEvents:input_type -> hashgraph.GetLatestEventsRequest
+	9,  // 16: hashgraph.Gossip.SyncEvents:input_type -> hashgraph.SyncEventsRequest
+	11, // 17: hashgraph.Gossip.GetLatestSnapshot:input_type -> hashgraph.GetLatestSnapshotRequest
+	8,  // 18: hashgraph.Gossip.GetLatestEvents:output_type -> hashgraph.GetLatestEventsResponse
+	10, // 19: hashgraph.Gossip.SyncEvents:output_type -> hashgraph.SyncEventsResponse
+	12, // 20: hashgraph.Gossip.GetLatestSnapshot:output_type -> hashgraph.GetLatestSnapshotResponse
+	18, // [18:21] is the sub-list for method output_type
+	15, // [15:18] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_hashgraph_proto_init() }
+func file_hashgraph_proto_init() {
+	if File_hashgraph_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_hashgraph_proto_rawDesc), len(file_hashgraph_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   16,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_hashgraph_proto_goTypes,
+		DependencyIndexes: file_hashgraph_proto_depIdxs,
+		MessageInfos:      file_hashgraph_proto_msgTypes,
+	}.Build()
+	File_hashgraph_proto = out.File
+	file_hashgraph_proto_goTypes = nil
+	file_hashgraph_proto_depIdxs = nil
+}
@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: hashgraph.proto
+
+// Wire format of the gossip between the members of a hashgraph. Every request and response carries the protocol
+// version of its sender as its first field, so that members reject the messages of another version instead of
+// decoding them with the wrong schema.
+
+package wire
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Gossip_GetLatestEvents_FullMethodName   = "/hashgraph.Gossip/GetLatestEvents"
+	Gossip_SyncEvents_FullMethodName        = "/hashgraph.Gossip/SyncEvents"
+	Gossip_GetLatestSnapshot_FullMethodName = "/hashgraph.Gossip/GetLatestSnapshot"
+)
+
+// GossipClient is the client API for Gossip service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Pull/push gossip: a member pulls the latest events that a peer knows, then pushes the events that the peer does not
+// know. A member that lags behind the pruned events of its peers pulls a snapshot instead.
+type GossipClient interface {
+	GetLatestEvents(ctx context.Context, in *GetLatestEventsRequest, opts ...grpc.CallOption) (*GetLatestEventsResponse, error)
+	SyncEvents(ctx context.Context, in *SyncEventsRequest, opts ...grpc.CallOption) (*SyncEventsResponse, error)
+	GetLatestSnapshot(ctx context.Context, in *GetLatestSnapshotRequest, opts ...grpc.CallOption) (*GetLatestSnapshotResponse, error)
+}
+
+type gossipClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGossipClient(cc grpc.ClientConnInterface) GossipClient {
+	return &gossipClient{cc}
+}
+
+func (c *gossipClient) GetLatestEvents(ctx context.Context, in *GetLatestEventsRequest, opts ...grpc.CallOption) (*GetLatestEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLatestEventsResponse)
+	err := c.cc.Invoke(ctx, Gossip_GetLatestEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gossipClient) SyncEvents(ctx context.Context, in *SyncEventsRequest, opts ...grpc.CallOption) (*SyncEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SyncEventsResponse)
+	err := c.cc.Invoke(ctx, Gossip_SyncEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gossipClient) GetLatestSnapshot(ctx context.Context, in *GetLatestSnapshotRequest, opts ...grpc.CallOption) (*GetLatestSnapshotResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetLatestSnapshotResponse)
+	err := c.cc.Invoke(ctx, Gossip_GetLatestSnapshot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GossipServer is the server API for Gossip service.
+// All implementations must embed UnimplementedGossipServer
+// for forward compatibility.
+//
+// Pull/push gossip: a member pulls the latest events that a peer knows, then pushes the events that the peer does not
+// know. A member that lags behind the pruned events of its peers pulls a snapshot instead.
+type GossipServer interface {
+	GetLatestEvents(context.Context, *GetLatestEventsRequest) (*GetLatestEventsResponse, error)
+	SyncEvents(context.Context, *SyncEventsRequest) (*SyncEventsResponse, error)
+	GetLatestSnapshot(context.Context, *GetLatestSnapshotRequest) (*GetLatestSnapshotResponse, error)
+	mustEmbedUnimplementedGossipServer()
+}
+
+// UnimplementedGossipServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGossipServer struct{}
+
+func (UnimplementedGossipServer) GetLatestEvents(context.Context, *GetLatestEventsRequest) (*GetLatestEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestEvents not implemented")
+}
+func (UnimplementedGossipServer) SyncEvents(context.Context, *SyncEventsRequest) (*SyncEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SyncEvents not implemented")
+}
+func (UnimplementedGossipServer) GetLatestSnapshot(context.Context, *GetLatestSnapshotRequest) (*GetLatestSnapshotResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLatestSnapshot not implemented")
+}
+func (UnimplementedGossipServer) mustEmbedUnimplementedGossipServer() {}
+func (UnimplementedGossipServer) testEmbeddedByValue()                {}
+
+// UnsafeGossipServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GossipServer will
+// result in compilation errors.
+type UnsafeGossipServer interface {
+	mustEmbedUnimplementedGossipServer()
+}
+
+func RegisterGossipServer(s grpc.ServiceRegistrar, srv GossipServer) {
+	// If the following call pancis, it indicates UnimplementedGossipServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Gossip_ServiceDesc, srv)
+}
+
+func _Gossip_GetLatestEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GossipServer).GetLatestEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gossip_GetLatestEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GossipServer).GetLatestEvents(ctx, req.(*GetLatestEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gossip_SyncEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SyncEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GossipServer).SyncEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gossip_SyncEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GossipServer).SyncEvents(ctx, req.(*SyncEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gossip_GetLatestSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLatestSnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GossipServer).GetLatestSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gossip_GetLatestSnapshot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GossipServer).GetLatestSnapshot(ctx, req.(*GetLatestSnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Gossip_ServiceDesc is the grpc.ServiceDesc for Gossip service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Gossip_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "hashgraph.Gossip",
+	HandlerType: (*GossipServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLatestEvents",
+			Handler:    _Gossip_GetLatestEvents_Handler,
+		},
+		{
+			MethodName: "SyncEvents",
+			Handler:    _Gossip_SyncEvents_Handler,
+		},
+		{
+			MethodName: "GetLatestSnapshot",
+			Handler:    _Gossip_GetLatestSnapshot_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "hashgraph.proto",
+}
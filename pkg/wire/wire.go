@@ -0,0 +1,216 @@
+package wire
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative hashgraph.proto dledger.proto
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"../hashgraph"
+)
+
+//ProtocolVersion : Version of the messages in this package. It is increased whenever a message changes in a way that
+// a member of the previous version can not decode, so that members of different versions refuse to gossip.
+const ProtocolVersion = 1
+
+//CheckVersion : Returns an error if a message was sent by a member of another protocol version. Servers return the
+// error to the caller as it is, clients should stop calling the member.
+func CheckVersion(version uint32) error {
+	if version != ProtocolVersion {
+		return status.Errorf(codes.FailedPrecondition, "protocol version %d is not supported, expected version %d", version, ProtocolVersion)
+	}
+	return nil
+}
+
+//FromEvent : Converts an event to its wire format, only the fields set by its owner are sent
+func FromEvent(e *hashgraph.Event) *Event {
+	transactions := make([][]byte, len(e.Transactions))
+	for i, tx := range e.Transactions {
+		transactions[i] = tx
+	}
+	return &Event{
+		Owner:           string(e.Owner),
+		Signature:       e.Signature,
+		SelfParentHash:  e.SelfParentHash,
+		OtherParentHash: e.OtherParentHash,
+		Timestamp:       timestamppb.New(e.Timestamp),
+		Transactions:    transactions,
+	}
+}
+
+//ToEvent : Converts an event from its wire format, the state of the event is calculated by the receiver
+func ToEvent(e *Event) *hashgraph.Event {
+	var transactions []hashgraph.Transaction
+	for _, tx := range e.GetTransactions() {
+		transactions = append(transactions, tx)
+	}
+	return &hashgraph.Event{
+		Owner:           hashgraph.MemberID(e.GetOwner()),
+		Signature:       e.GetSignature(),
+		SelfParentHash:  e.GetSelfParentHash(),
+		OtherParentHash: e.GetOtherParentHash(),
+		Timestamp:       toTime(e.GetTimestamp()),
+		Transactions:    transactions,
+	}
+}
+
+//FromEvents : Converts a map of member IDs to events of those members to its wire format
+func FromEvents(events map[hashgraph.MemberID][]*hashgraph.Event) map[string]*EventList {
+	lists := make(map[string]*EventList, len(events))
+	for id, memberEvents := range events {
+		list := &EventList{Events: make([]*Event, len(memberEvents))}
+		for i, e := range memberEvents {
+			list.Events[i] = FromEvent(e)
+		}
+		lists[string(id)] = list
+	}
+	return lists
+}
+
+//ToEvents : Converts a map of member IDs to events of those members from its wire format
+func ToEvents(lists map[string]*EventList) map[hashgraph.MemberID][]*hashgraph.Event {
+	events := make(map[hashgraph.MemberID][]*hashgraph.Event, len(lists))
+	for id, list := range lists {
+		for _, e := range list.GetEvents() {
+			events[hashgraph.MemberID(id)] = append(events[hashgraph.MemberID(id)], ToEvent(e))
+		}
+	}
+	return events
+}
+
+//FromTips : Converts the latest events that a member knows to their wire format
+func FromTips(tips map[hashgraph.MemberID][]string) map[string]*HashList {
+	lists := make(map[string]*HashList, len(tips))
+	for id, hashes := range tips {
+		lists[string(id)] = &HashList{Hashes: hashes}
+	}
+	return lists
+}
+
+//ToTips : Converts the latest events that a member knows from their wire format
+func ToTips(lists map[string]*HashList) map[hashgraph.MemberID][]string {
+	tips := make(map[hashgraph.MemberID][]string, len(lists))
+	for id, list := range lists {
+		tips[hashgraph.MemberID(id)] = list.GetHashes()
+	}
+	return tips
+}
+
+//FromSnapshot : Converts a snapshot to its wire format, the events are sent with their states
+func FromSnapshot(s *hashgraph.Snapshot) *Snapshot {
+	snapshot := &Snapshot{
+		Round:               s.Round,
+		Owner:               string(s.Owner),
+		State:               s.State,
+		ConsensusEventCount: int64(s.ConsensusEventCount),
+		LastRoundReceived:   s.LastRoundReceived,
+		Events:              make([]*SnapshotEvent, len(s.Events)),
+		PrunedEventCount:    make(map[string]int64, len(s.PrunedEventCount)),
+		Signature:           s.Signature,
+		StateSignatures:     FromStateSignatures(s.StateSignatures),
+		ConsensusDigest:     s.ConsensusDigest,
+		DigestBaseRound:     s.DigestBaseRound,
+		DigestBase:          s.DigestBase,
+	}
+	for i := range s.Events {
+		state := s.Events[i].State
+		snapshot.Events[i] = &SnapshotEvent{
+			Event: FromEvent(&s.Events[i].Event),
+			State: &EventState{
+				Round:              state.Round,
+				IsWitness:          state.IsWitness,
+				IsFamous:           state.IsFamous,
+				IsFameDecided:      state.IsFameDecided,
+				RoundReceived:      state.RoundReceived,
+				ConsensusTimestamp: timestamppb.New(state.ConsensusTimestamp),
+				Latency:            durationpb.New(state.Latency),
+			},
+		}
+	}
+	for id, count := range s.PrunedEventCount {
+		snapshot.PrunedEventCount[string(id)] = int64(count)
+	}
+	return snapshot
+}
+
+//ToSnapshot : Converts a snapshot from its wire format. The signature of the snapshot is verified by the receiver
+// when the snapshot is installed.
+func ToSnapshot(s *Snapshot) *hashgraph.Snapshot {
+	snapshot := &hashgraph.Snapshot{
+		Round:               s.GetRound(),
+		Owner:               hashgraph.MemberID(s.GetOwner()),
+		State:               s.GetState(),
+		ConsensusEventCount: int(s.GetConsensusEventCount()),
+		LastRoundReceived:   s.GetLastRoundReceived(),
+		Events:              make([]hashgraph.SnapshotEvent, len(s.GetEvents())),
+		PrunedEventCount:    make(map[hashgraph.MemberID]int, len(s.GetPrunedEventCount())),
+		Signature:           s.GetSignature(),
+		StateSignatures:     ToStateSignatures(s.GetStateSignatures()),
+		ConsensusDigest:     s.GetConsensusDigest(),
+		DigestBaseRound:     s.GetDigestBaseRound(),
+		DigestBase:          s.GetDigestBase(),
+	}
+	for i, e := range s.GetEvents() {
+		state := e.GetState()
+		snapshot.Events[i] = hashgraph.SnapshotEvent{
+			Event: *ToEvent(e.GetEvent()),
+			State: hashgraph.EventState{
+				Round:              state.GetRound(),
+				IsWitness:          state.GetIsWitness(),
+				IsFamous:           state.GetIsFamous(),
+				IsFameDecided:      state.GetIsFameDecided(),
+				RoundReceived:      state.GetRoundReceived(),
+				ConsensusTimestamp: toTime(state.GetConsensusTimestamp()),
+				Latency:            state.GetLatency().AsDuration(),
+			},
+		}
+	}
+	for id, count := range s.GetPrunedEventCount() {
+		snapshot.PrunedEventCount[hashgraph.MemberID(id)] = int(count)
+	}
+	return snapshot
+}
+
+//FromStateSignatures : Converts signatures over states to their wire format
+func FromStateSignatures(signatures []hashgraph.StateSignature) []*StateSignature {
+	wireSignatures := make([]*StateSignature, len(signatures))
+	for i, s := range signatures {
+		wireSignatures[i] = &StateSignature{
+			Round:               s.Round,
+			ConsensusEventCount: int64(s.ConsensusEventCount),
+			StateHash:           s.StateHash,
+			ConsensusDigest:     s.ConsensusDigest,
+			Signer:              string(s.Signer),
+			Signature:           s.Signature,
+		}
+	}
+	return wireSignatures
+}
+
+//ToStateSignatures : Converts signatures over states from their wire format, the receiver verifies them
+func ToStateSignatures(wireSignatures []*StateSignature) []hashgraph.StateSignature {
+	var signatures []hashgraph.StateSignature
+	for _, s := range wireSignatures {
+		signatures = append(signatures, hashgraph.StateSignature{
+			Round:               s.GetRound(),
+			ConsensusEventCount: int(s.GetConsensusEventCount()),
+			StateHash:           s.GetStateHash(),
+			ConsensusDigest:     s.GetConsensusDigest(),
+			Signer:              hashgraph.MemberID(s.GetSigner()),
+			Signature:           s.GetSignature(),
+		})
+	}
+	return signatures
+}
+
+// Converts a timestamp from its wire format, in UTC like the times in the signed encoding of a snapshot
+func toTime(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime()
+}